@@ -0,0 +1,149 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ProviderConfigAnnotation is the namespace annotation with the name of AWSProviderConfig
+	// used by GlueJobs in the namespace, which don't reference AWSProviderConfig
+	ProviderConfigAnnotation = "gluejobs.aws.90poe.io/provider-config"
+	// ProviderConfigIndex is the field index of GlueJobs by the name of referenced AWSProviderConfig
+	ProviderConfigIndex = "providerConfigRef"
+)
+
+// ProviderConfigReference is the reference to AWSProviderConfig
+type ProviderConfigReference struct {
+	// Name is the name of AWSProviderConfig
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// AWSAssumeRole defines IAM role to be assumed with operator credentials
+type AWSAssumeRole struct {
+	// RoleARN is the ARN of IAM role to assume
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::\d{12}:role\/.+$`
+	RoleARN string `json:"roleArn"`
+
+	// ExternalID is the external ID required by the trust policy of the role
+	ExternalID string `json:"externalId,omitempty"`
+
+	// SessionName is the name of the role session, defaults to glue-jobs-operator
+	SessionName string `json:"sessionName,omitempty"`
+
+	// SessionTags are the session tags passed when the role is assumed
+	SessionTags map[string]string `json:"sessionTags,omitempty"`
+
+	// Duration is the duration of the role session
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// AWSWebIdentity defines IAM role to be assumed with web identity token
+type AWSWebIdentity struct {
+	// RoleARN is the ARN of IAM role to assume
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::\d{12}:role\/.+$`
+	RoleARN string `json:"roleArn"`
+
+	// TokenFile is the path to the file with web identity token, mounted into operator pod
+	// +required
+	// +kubebuilder:validation:Required
+	TokenFile string `json:"tokenFile"`
+
+	// SessionName is the name of the role session, defaults to glue-jobs-operator
+	SessionName string `json:"sessionName,omitempty"`
+}
+
+// AWSEndpoints defines AWS service endpoint overrides, e.g. for VPC endpoints or local stand-ins
+type AWSEndpoints struct {
+	// Glue is the endpoint URL of AWS Glue
+	Glue string `json:"glue,omitempty"`
+
+	// STS is the endpoint URL of AWS STS
+	STS string `json:"sts,omitempty"`
+
+	// S3 is the endpoint URL of AWS S3, S3-compatible endpoints are addressed with path-style URLs
+	S3 string `json:"s3,omitempty"`
+
+	// SecretsManager is the endpoint URL of AWS Secrets Manager
+	SecretsManager string `json:"secretsManager,omitempty"`
+
+	// SSM is the endpoint URL of AWS Systems Manager
+	SSM string `json:"ssm,omitempty"`
+
+	// IAM is the endpoint URL of AWS IAM
+	IAM string `json:"iam,omitempty"`
+}
+
+// AWSProviderConfigSpec defines AWS account, region and credentials used to manage Glue Jobs
+// +kubebuilder:validation:XValidation:rule="!(has(self.assumeRole) && has(self.webIdentity))",message="only one of assumeRole and webIdentity can be set"
+type AWSProviderConfigSpec struct {
+	// Region is the AWS region of Glue Jobs, defaults to the region of the operator
+	Region string `json:"region,omitempty"`
+
+	// AssumeRole is the IAM role assumed with the operator credentials
+	AssumeRole *AWSAssumeRole `json:"assumeRole,omitempty"`
+
+	// WebIdentity is the IAM role assumed with web identity token
+	WebIdentity *AWSWebIdentity `json:"webIdentity,omitempty"`
+
+	// Endpoints are the AWS service endpoint overrides
+	Endpoints *AWSEndpoints `json:"endpoints,omitempty"`
+
+	// ScriptBucket is the S3 bucket, which scripts from script sources are uploaded to,
+	// defaults to the script bucket of the operator
+	ScriptBucket string `json:"scriptBucket,omitempty"`
+
+	// AllowedNamespaces are the namespaces of GlueJobs, which may use the AWSProviderConfig,
+	// empty means GlueJobs of all namespaces
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowedAssumeRoles are the ARNs of IAM roles, which placements using the AWSProviderConfig
+	// may assume instead of its own role
+	AllowedAssumeRoles []string `json:"allowedAssumeRoles,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// AWSProviderConfig is the Schema for the awsproviderconfigs API
+type AWSProviderConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AWSProviderConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AWSProviderConfigList contains a list of AWSProviderConfig
+type AWSProviderConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AWSProviderConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AWSProviderConfig{}, &AWSProviderConfigList{})
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/glue@v1.63.0/types#JobCommand
+// +kubebuilder:validation:XValidation:rule="has(self.scriptLocation) != has(self.scriptSource)",message="exactly one of scriptLocation and scriptSource must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.scriptVersionId) || has(self.scriptLocation)",message="scriptVersionId requires scriptLocation"
 type GlueJobCommand struct {
 	// +required
 	// +kubebuilder:validation:Required
@@ -30,14 +33,153 @@ type GlueJobCommand struct {
 	// +kubebuilder:default=3
 	// +kubebuilder:validation:Format=`^(2|3)$`
 	PythonVersion int `json:"pythonVersion,omitempty"`
-	// +kubebuilder:default=glueetl
+	// Runtime is the Ray runtime of the job (e.g. Ray2.4), only used by glueray jobs.
+	// Defaults to Ray2.4 for glueray jobs
 	Runtime string `json:"runtime,omitempty"`
-	// +required
-	// +kubebuilder:validation:Required
+	// ScriptLocation is the S3 path of the script
 	// +kubebuilder:validation:MaxLength=1024
 	// +kubebuilder:validation:MinLength=10
-	// +kubebuilder:validation:Format=`^s3://.+\/.+$`
-	ScriptLocation string `json:"scriptLocation"`
+	// +kubebuilder:validation:Pattern=`^s3://.+\/.+$`
+	ScriptLocation string `json:"scriptLocation,omitempty"`
+	// ScriptVersionID pins the script to the version of the object in versioned bucket of scriptLocation,
+	// the operator copies the version to the script bucket and the Glue Job runs the copy
+	// +kubebuilder:validation:MaxLength=1024
+	ScriptVersionID string `json:"scriptVersionId,omitempty"`
+	// ScriptSource is the script, which the operator uploads to the script bucket, instead of scriptLocation
+	ScriptSource *GlueJobScriptSource `json:"scriptSource,omitempty"`
+}
+
+// GlueJobScriptSource is the source of the script uploaded to S3 by the operator
+// +kubebuilder:validation:XValidation:rule="[has(self.configMapKeyRef), has(self.inline), has(self.gitRef)].filter(x, x).size() == 1",message="exactly one of configMapKeyRef, inline and gitRef must be set"
+type GlueJobScriptSource struct {
+	// ConfigMapKeyRef selects the key of ConfigMap in the namespace of GlueJob with the script,
+	// the key is used as the file name of the script
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Inline is the text of the script
+	Inline string `json:"inline,omitempty"`
+	// GitRef selects the file of GlueScriptSource in the namespace of GlueJob with the script
+	GitRef *GlueJobGitScriptRef `json:"gitRef,omitempty"`
+}
+
+// GlueJobGitScriptRef is the reference to the script in Git repository of GlueScriptSource
+type GlueJobGitScriptRef struct {
+	// Name is the name of GlueScriptSource
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// File is the path of the script relative to the path of GlueScriptSource
+	// +kubebuilder:validation:MinLength=1
+	File string `json:"file"`
+}
+
+// GlueJobPythonModules are the Python modules, which the operator bundles into zip file passed as --extra-py-files
+// +kubebuilder:validation:XValidation:rule="has(self.configMaps) || has(self.s3Prefix)",message="configMaps or s3Prefix must be set"
+type GlueJobPythonModules struct {
+	// ConfigMaps are ConfigMaps in the namespace of GlueJob, their keys are files of the bundle
+	ConfigMaps []GlueJobModulesConfigMap `json:"configMaps,omitempty"`
+	// S3Prefix is the S3 path of staged modules, objects under it are files of the bundle with paths relative to it
+	// +kubebuilder:validation:Pattern=`^s3://.+$`
+	S3Prefix string `json:"s3Prefix,omitempty"`
+}
+
+// GlueJobModulesConfigMap is ConfigMap with files of Python modules
+type GlueJobModulesConfigMap struct {
+	// Name is the name of ConfigMap
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Path is the directory of the files in the bundle, e.g. the package name
+	Path string `json:"path,omitempty"`
+}
+
+// GlueJobSourceControl is the source control repository of the Glue Job
+// https://docs.aws.amazon.com/glue/latest/dg/edit-job-add-source.html
+type GlueJobSourceControl struct {
+	// Provider is the provider of the repository
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=GITHUB;GITLAB;BITBUCKET;AWS_CODE_COMMIT
+	Provider string `json:"provider"`
+	// Repository is the name of the repository
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+	// Owner is the owner of the repository
+	Owner string `json:"owner,omitempty"`
+	// Branch is the branch of the repository
+	Branch string `json:"branch,omitempty"`
+	// Folder is the folder of the repository with the job
+	Folder string `json:"folder,omitempty"`
+	// AuthStrategy is the type of the auth token
+	// +kubebuilder:validation:Enum=PERSONAL_ACCESS_TOKEN;AWS_SECRETS_MANAGER
+	AuthStrategy string `json:"authStrategy,omitempty"`
+	// AuthTokenSecretRef selects the key of Secret in the namespace of GlueJob with the personal access token
+	// or the name of the secret in AWS Secrets Manager, it's only used to pull and push
+	AuthTokenSecretRef *corev1.SecretKeySelector `json:"authTokenSecretRef,omitempty"`
+}
+
+// GlueJobArgumentsFromSource is ConfigMap or Secret, which keys are added to default arguments
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.secretRef)",message="exactly one of configMapRef and secretRef must be set"
+type GlueJobArgumentsFromSource struct {
+	// Prefix is prepended to the keys, e.g. "--" for keys without leading dashes
+	Prefix string `json:"prefix,omitempty"`
+	// ConfigMapRef is ConfigMap in the namespace of GlueJob
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+	// SecretRef is Secret in the namespace of GlueJob, its values are stored in plaintext default arguments
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// GlueJobArgumentValueFrom is the key of ConfigMap or Secret with the value of default argument
+// +kubebuilder:validation:XValidation:rule="has(self.configMapKeyRef) != has(self.secretKeyRef)",message="exactly one of configMapKeyRef and secretKeyRef must be set"
+type GlueJobArgumentValueFrom struct {
+	// ConfigMapKeyRef selects the key of ConfigMap in the namespace of GlueJob
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// SecretKeyRef selects the key of Secret in the namespace of GlueJob, its value is stored in plaintext default argument
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// GlueJobAWSSecret references Secrets Manager secret or SSM parameter, which ARN is passed to the script
+type GlueJobAWSSecret struct {
+	// Name is the name of the secret in the script, the ARN is passed in --<name>-secret-arn argument
+	// for Secrets Manager secrets and in --<name>-parameter-arn argument for SSM parameters
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+(-[a-z0-9]+)*$`
+	Name string `json:"name"`
+	// ARN is the ARN of Secrets Manager secret or SSM parameter, which must be readable by the role of Glue Job
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^arn:[^:]+:(secretsmanager|ssm):[^:]*:[0-9]*:(secret:|parameter/).+$`
+	ARN string `json:"arn"`
+}
+
+// GlueJobSmokeTest is the job run started, when the script of the Glue Job changes
+type GlueJobSmokeTest struct {
+	// Arguments are the arguments of the job run, which override default arguments
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// GlueJobRay holds the Ray specific settings of a glueray job
+// https://docs.aws.amazon.com/glue/latest/dg/author-job-ray-job-parameters.html
+type GlueJobRay struct {
+	// PipInstall is the list of Python packages to install with pip, passed as --pip-install
+	PipInstall []string `json:"pipInstall,omitempty"`
+	// S3PyModules is the list of S3 paths to Python modules, passed as --s3-py-modules
+	S3PyModules []string `json:"s3PyModules,omitempty"`
+	// WorkingDir is the S3 path to a zip file with the working directory, passed as --working-dir
+	// +kubebuilder:validation:Pattern=`^s3://.+\/.+$`
+	WorkingDir string `json:"workingDir,omitempty"`
+	// MinWorkers is the minimum number of workers the Ray cluster auto-scales down to,
+	// passed as --min-workers. NumberOfWorkers is the maximum number of workers.
+	// +kubebuilder:validation:Minimum=0
+	MinWorkers *int32 `json:"minWorkers,omitempty"`
+}
+
+// GlueJobNotificationProperty specifies notification settings of the Glue Job
+type GlueJobNotificationProperty struct {
+	// NotifyDelayAfter is the number of minutes to wait after a job run starts
+	// before sending a job run delay notification
+	// +kubebuilder:validation:Minimum=1
+	NotifyDelayAfter *int32 `json:"notifyDelayAfter,omitempty"`
 }
 
 type GlueJobExecutionProperty struct {
@@ -45,17 +187,53 @@ type GlueJobExecutionProperty struct {
 	MaxConcurrentRuns int32 `json:"maxConcurrentRuns,omitempty"`
 }
 
+const (
+	// ApprovedPlanAnnotation is the GlueJob annotation with the hash of approved plan
+	ApprovedPlanAnnotation = "gluejobs.aws.90poe.io/approved-plan"
+	// PausedAnnotation is the GlueJob annotation, which set to "true" pauses reconciliation of GlueJob
+	PausedAnnotation = "gluejobs.aws.90poe.io/paused"
+	// ResyncPeriodAnnotation is the GlueJob annotation overriding the operator resync period, e.g. "30m", "0" disables resync
+	ResyncPeriodAnnotation = "gluejobs.aws.90poe.io/resync-period"
+	// ConfigMapIndex is the field index of GlueJobs by the names of referenced ConfigMaps
+	ConfigMapIndex = "configMapRef"
+	// SourceControlPullAnnotation is the GlueJob annotation, which pulls the Glue Job from source control
+	// once per value, e.g. a timestamp
+	SourceControlPullAnnotation = "gluejobs.aws.90poe.io/pull-from-source-control"
+	// SourceControlPushAnnotation is the GlueJob annotation, which pushes the Glue Job to source control
+	// once per value, e.g. a timestamp
+	SourceControlPushAnnotation = "gluejobs.aws.90poe.io/push-to-source-control"
+	// SecretIndex is the field index of GlueJobs by the names of referenced Secrets
+	SecretIndex = "secretRef"
+	// ShardAnnotation is the GlueJob annotation with the shard of the operator instance, which reconciles GlueJob
+	ShardAnnotation = "gluejobs.aws.90poe.io/shard"
+)
+
+// Management policies of GlueJob
+const (
+	ManagementPolicyFull        = "Full"
+	ManagementPolicyObserveOnly = "ObserveOnly"
+	ManagementPolicyCreateOnly  = "CreateOnly"
+	ManagementPolicyNoDelete    = "NoDelete"
+)
+
+// Policies for Glue Jobs deleted outside of the operator
+const (
+	MissingJobPolicyRecreate    = "Recreate"
+	MissingJobPolicyMarkMissing = "MarkMissing"
+)
+
 // GlueJobSpec defines the desired state of GlueJob
+// +kubebuilder:validation:XValidation:rule="!has(self.ray) || self.command.name.lowerAscii() == 'glueray'",message="ray can only be set for glueray jobs"
 type GlueJobSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// Name is the name of the Glue Job
-	// +required
-	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:MaxLength=1024
-	// +kubebuilder:validation:MinLength=10
-	Name string `json:"name"`
+	// Name is the name of the Glue Job. If it's not set, the name is derived
+	// from the namespace and the name of GlueJob by the operator
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name,omitempty"`
 
 	// Command is the Glue Job Command https://pkg.go.dev/github.com/aws/aws-sdk-go-v2/service/glue@v1.63.0/types#JobCommand
 	// +required
@@ -83,13 +261,14 @@ type GlueJobSpec struct {
 	// +kubebuilder:default=2
 	NumberOfWorkers int32 `json:"numberOfWorkers,omitempty"`
 
-	// WorkerType is the type of worker to be used by the Glue Job
-	// +kubebuilder:default=G.1X
+	// WorkerType is the type of worker to be used by the Glue Job.
+	// Defaults to Z.2X for glueray jobs and to G.1X for all other jobs
+	// +kubebuilder:validation:Enum=Standard;G.1X;G.2X;G.025X;G.4X;G.8X;Z.2X
 	WorkerType string `json:"workerType,omitempty"`
 
-	// ExecutionProperty is the execution property to be used by the Glue Job
-	// +kubebuilder:default=FLEX
-	// +kubebuilder:validation:Format=`^(FLEX|STANDARD)$`
+	// ExecutionClass is the execution class to be used by the Glue Job.
+	// Defaults to STANDARD for glueray jobs and to FLEX for all other jobs
+	// +kubebuilder:validation:Enum=FLEX;STANDARD
 	ExecutionClass string `json:"executionClass,omitempty"`
 
 	// ExecutionProperty is the execution property to be used by the Glue Job
@@ -103,8 +282,126 @@ type GlueJobSpec struct {
 	// DefaultArguments is the default arguments to be used by the Glue Job
 	DefaultArguments map[string]string `json:"defaultArguments,omitempty"`
 
+	// DefaultArgumentsFrom are ConfigMaps and Secrets, which keys are added to default arguments.
+	// Keys of later sources override earlier ones, defaultArguments override all of them
+	DefaultArgumentsFrom []GlueJobArgumentsFromSource `json:"defaultArgumentsFrom,omitempty"`
+
+	// DefaultArgumentsValueFrom are default arguments with values from keys of ConfigMaps and Secrets,
+	// they override defaultArguments and defaultArgumentsFrom
+	DefaultArgumentsValueFrom map[string]GlueJobArgumentValueFrom `json:"defaultArgumentsValueFrom,omitempty"`
+
+	// AWSSecrets are Secrets Manager secrets and SSM parameters read by the script, the operator checks
+	// that they exist and are readable by the role, and passes their ARNs in default arguments
+	// +listType=map
+	// +listMapKey=name
+	AWSSecrets []GlueJobAWSSecret `json:"awsSecrets,omitempty"`
+
 	// Tags is the tags to be set on the Glue Job
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// Ray holds the Ray specific settings, only allowed for glueray jobs
+	Ray *GlueJobRay `json:"ray,omitempty"`
+
+	// Description is the description of the Glue Job
+	// +kubebuilder:validation:MaxLength=2048
+	Description string `json:"description,omitempty"`
+
+	// LogURI is the location of logs of the Glue Job
+	LogURI string `json:"logUri,omitempty"`
+
+	// Connections is the list of Glue connections used by the Glue Job
+	Connections []string `json:"connections,omitempty"`
+
+	// SecurityConfiguration is the name of Glue security configuration to be used by the Glue Job
+	// +kubebuilder:validation:MaxLength=255
+	SecurityConfiguration string `json:"securityConfiguration,omitempty"`
+
+	// NotificationProperty is the notification settings of the Glue Job
+	NotificationProperty *GlueJobNotificationProperty `json:"notificationProperty,omitempty"`
+
+	// NonOverridableArguments is the arguments of the Glue Job, which can't be overridden by job runs
+	NonOverridableArguments map[string]string `json:"nonOverridableArguments,omitempty"`
+
+	// MaintenanceWindow is the maintenance window of gluestreaming jobs, e.g. "Sun:1"
+	// https://docs.aws.amazon.com/glue/latest/dg/monitor-maintenance-window.html
+	// +kubebuilder:validation:Pattern=`^(Sun|Mon|Tue|Wed|Thu|Fri|Sat):([01]?[0-9]|2[0-3])$`
+	MaintenanceWindow string `json:"maintenanceWindow,omitempty"`
+
+	// JobMode is the mode in which the Glue Job was authored
+	// +kubebuilder:validation:Enum=SCRIPT;VISUAL;NOTEBOOK
+	JobMode string `json:"jobMode,omitempty"`
+
+	// ProviderConfigRef is the reference to AWSProviderConfig with AWS account, region and credentials
+	// of the Glue Job. If it's not set, AWSProviderConfig from the namespace annotation is used,
+	// otherwise the operator credentials and region are used
+	ProviderConfigRef *ProviderConfigReference `json:"providerConfigRef,omitempty"`
+
+	// ManagementPolicy defines which mutations of the Glue Job on AWS the operator may make:
+	// Full - create, update and delete; ObserveOnly - none, planned changes are only reported;
+	// CreateOnly - only create missing jobs; NoDelete - create and update, but never delete
+	// +kubebuilder:default=Full
+	// +kubebuilder:validation:Enum=Full;ObserveOnly;CreateOnly;NoDelete
+	ManagementPolicy string `json:"managementPolicy,omitempty"`
+
+	// MissingJobPolicy defines what happens with the Glue Job deleted outside of the operator:
+	// Recreate - it's recreated; MarkMissing - GlueJob is marked not ready with Missing reason
+	// +kubebuilder:default=Recreate
+	// +kubebuilder:validation:Enum=Recreate;MarkMissing
+	MissingJobPolicy string `json:"missingJobPolicy,omitempty"`
+
+	// RequireApproval makes changes of the Glue Job on AWS staged: they are written to status.pendingPlan
+	// and applied only once the gluejobs.aws.90poe.io/approved-plan annotation is set to the plan hash
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// PythonModules are bundled by the operator, uploaded to the script bucket and added to --extra-py-files
+	PythonModules *GlueJobPythonModules `json:"pythonModules,omitempty"`
+
+	// SourceControlDetails is the source control repository of the Glue Job, the job is pulled from
+	// and pushed to the repository with gluejobs.aws.90poe.io/pull-from-source-control and
+	// gluejobs.aws.90poe.io/push-to-source-control annotations
+	SourceControlDetails *GlueJobSourceControl `json:"sourceControlDetails,omitempty"`
+
+	// SmokeTest starts a job run, when the script in S3 changes (e.g. new version is uploaded by CI)
+	SmokeTest *GlueJobSmokeTest `json:"smokeTest,omitempty"`
+
+	// Placements are the AWS accounts and regions the Glue Job is replicated to, e.g. for DR.
+	// If it's not set, the Glue Job is created only in the account and region of providerConfigRef
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Placements []GlueJobPlacement `json:"placements,omitempty"`
+}
+
+// GlueJobPlacement defines AWS account and region of a Glue Job replica with optional overrides
+type GlueJobPlacement struct {
+	// Name identifies the placement in GlueJob status
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// ProviderConfigRef is the reference to AWSProviderConfig of the placement,
+	// defaults to AWSProviderConfig of GlueJob
+	ProviderConfigRef *ProviderConfigReference `json:"providerConfigRef,omitempty"`
+
+	// Region overrides the region of AWSProviderConfig
+	Region string `json:"region,omitempty"`
+
+	// AssumeRole overrides the IAM role of AWSProviderConfig, the role must be one of
+	// allowedAssumeRoles of the AWSProviderConfig
+	AssumeRole *AWSAssumeRole `json:"assumeRole,omitempty"`
+
+	// ScriptLocation overrides the command script location, e.g. to use a bucket in the same region
+	// +kubebuilder:validation:Pattern=`^s3://.+\/.+$`
+	ScriptLocation string `json:"scriptLocation,omitempty"`
+
+	// Role overrides the IAM role used by the Glue Job
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::\d{12}:role\/.+$`
+	Role string `json:"role,omitempty"`
+
+	// DefaultArguments are merged over the default arguments of the Glue Job
+	DefaultArguments map[string]string `json:"defaultArguments,omitempty"`
 }
 
 // GlueJobStatus defines the observed state of GlueJob
@@ -112,11 +409,103 @@ type GlueJobStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// ResolvedName is the name of the Glue Job on AWS
+	ResolvedName string `json:"resolvedName,omitempty"`
+
+	// PendingPlan is the plan of changes, which awaits approval
+	PendingPlan *GlueJobPlan `json:"pendingPlan,omitempty"`
+
+	// AppliedPlanHash is the hash of the last applied approved plan
+	AppliedPlanHash string `json:"appliedPlanHash,omitempty"`
+
+	// Placements store the status of the Glue Job in every placement
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Placements []GlueJobPlacementStatus `json:"placements,omitempty"`
+
 	// Conditions store the status conditions of the GlueJob instances
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// GlueJobPlan defines changes of the Glue Job on AWS, which await approval
+type GlueJobPlan struct {
+	// Hash identifies the plan, it changes with the spec and with the changes
+	Hash string `json:"hash"`
+
+	// Changes are the planned mutations of the Glue Job in all placements
+	Changes []string `json:"changes,omitempty"`
+}
+
+// GlueJobPlacementStatus defines the observed state of the Glue Job in a placement
+type GlueJobPlacementStatus struct {
+	// Name is the name of the placement
+	Name string `json:"name"`
+
+	// AccountID is the AWS account of the placement
+	AccountID string `json:"accountId,omitempty"`
+
+	// Region is the AWS region of the placement
+	Region string `json:"region,omitempty"`
+
+	// Exists is true if the Glue Job was created or found on AWS, it's used to detect
+	// Glue Jobs deleted outside of the operator
+	Exists bool `json:"exists,omitempty"`
+
+	// Recreations is the number of times the Glue Job was recreated after it was deleted outside of the operator
+	Recreations int32 `json:"recreations,omitempty"`
+
+	// PlannedChanges are the mutations of the Glue Job on AWS, which were not made
+	// because of the management policy
+	PlannedChanges []string `json:"plannedChanges,omitempty"`
+
+	// MissingArtifacts are the S3 URIs of the script and dependencies, which don't exist
+	MissingArtifacts []string `json:"missingArtifacts,omitempty"`
+
+	// ScriptObject is the S3 path of the script uploaded by the operator from script source
+	ScriptObject string `json:"scriptObject,omitempty"`
+
+	// PinnedScript is the pinned script version ScriptObject was copied from as <scriptLocation>?versionId=<id>,
+	// the version isn't copied again until it changes
+	PinnedScript string `json:"pinnedScript,omitempty"`
+
+	// ModulesObject is the S3 path of the bundle of Python modules uploaded by the operator
+	ModulesObject string `json:"modulesObject,omitempty"`
+
+	// ModulesHash is the hash of ConfigMaps and S3 objects ModulesObject was bundled from,
+	// staged modules aren't read again until it changes
+	ModulesHash string `json:"modulesHash,omitempty"`
+
+	// ScriptCommit is the commit of GlueScriptSource, which the script of the Glue Job was uploaded from
+	ScriptCommit string `json:"scriptCommit,omitempty"`
+
+	// SourceControlPull is the value of pull annotation, which the Glue Job was pulled for
+	SourceControlPull string `json:"sourceControlPull,omitempty"`
+
+	// SourceControlPush is the value of push annotation, which the Glue Job was pushed for
+	SourceControlPush string `json:"sourceControlPush,omitempty"`
+
+	// SourceControlCommit is the last commit of the source control repository synced with the Glue Job
+	SourceControlCommit string `json:"sourceControlCommit,omitempty"`
+
+	// ScriptETag is the ETag of the script in S3, which the Glue Job runs
+	ScriptETag string `json:"scriptETag,omitempty"`
+
+	// ScriptVersionID is the version of the script in S3, which the Glue Job runs, if the bucket is versioned
+	ScriptVersionID string `json:"scriptVersionId,omitempty"`
+
+	// SmokeTestRunID is the ID of the last job run started, when the script changed
+	SmokeTestRunID string `json:"smokeTestRunId,omitempty"`
+
+	// Placement is the last applied placement, it's used to delete the Glue Job,
+	// when the placement is removed from the spec
+	Placement GlueJobPlacement `json:"placement"`
+
+	// Conditions store the status conditions of the Glue Job in the placement
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 
@@ -138,6 +527,16 @@ type GlueJobList struct {
 	Items           []GlueJob `json:"items"`
 }
 
+// CreatedBefore will return true if GlueJob was created before other GlueJob. Of GlueJobs with the same
+// name of Glue Job on AWS, the earliest created one manages it
+func (r *GlueJob) CreatedBefore(other *GlueJob) bool {
+	if !r.CreationTimestamp.Equal(&other.CreationTimestamp) {
+		return r.CreationTimestamp.Before(&other.CreationTimestamp)
+	}
+	// same second, order by namespace and name to have stable winner
+	return r.Namespace+"/"+r.Name < other.Namespace+"/"+other.Name
+}
+
 func init() {
 	SchemeBuilder.Register(&GlueJob{}, &GlueJobList{})
 }
@@ -0,0 +1,129 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// GlueJobNameIndex is the field index of GlueJobs by the name of Glue Job on AWS.
+// It must be registered in the Manager cache before the webhook is used.
+const GlueJobNameIndex = "awsJobName"
+
+// log is for logging in this package.
+var gluejoblog = logf.Log.WithName("gluejob-resource")
+
+// GlueJobValidator validates GlueJobs against other GlueJobs in the cluster
+// +kubebuilder:object:generate=false
+type GlueJobValidator struct {
+	client.Reader
+	// JobName returns the name of Glue Job on AWS for GlueJob
+	JobName func(*GlueJob) (string, error)
+}
+
+// SetupWebhookWithManager will register validating webhook for GlueJob with the Manager
+func (r *GlueJob) SetupWebhookWithManager(mgr ctrl.Manager, jobName func(*GlueJob) (string, error)) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&GlueJobValidator{Reader: mgr.GetClient(), JobName: jobName}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-aws-90poe-io-v1alpha1-gluejob,mutating=false,failurePolicy=fail,sideEffects=None,groups=aws.90poe.io,resources=gluejobs,verbs=create;update,versions=v1alpha1,name=vgluejob.kb.io,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &GlueJobValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *GlueJobValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	glueJob, ok := obj.(*GlueJob)
+	if !ok {
+		return nil, fmt.Errorf("expected GlueJob, got %T", obj)
+	}
+	gluejoblog.V(1).Info("validate create", "name", glueJob.Name)
+	return nil, v.validateUniqueName(ctx, glueJob, nil)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *GlueJobValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	glueJob, ok := newObj.(*GlueJob)
+	if !ok {
+		return nil, fmt.Errorf("expected GlueJob, got %T", newObj)
+	}
+	oldGlueJob, ok := oldObj.(*GlueJob)
+	if !ok {
+		return nil, fmt.Errorf("expected GlueJob, got %T", oldObj)
+	}
+	gluejoblog.V(1).Info("validate update", "name", glueJob.Name)
+	// updates of metadata (e.g. finalizers) and of GlueJobs being deleted must never be blocked
+	if glueJob.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldGlueJob.Spec, glueJob.Spec) {
+		return nil, nil
+	}
+	return nil, v.validateUniqueName(ctx, glueJob, oldGlueJob)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *GlueJobValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateUniqueName will reject GlueJob, which Glue Job name is already used by another GlueJob.
+// On update (oldGlueJob isn't nil) GlueJob is rejected only if it changes the name to the one used by
+// GlueJob created earlier, which manages the Glue Job
+func (v *GlueJobValidator) validateUniqueName(ctx context.Context, glueJob, oldGlueJob *GlueJob) error {
+	name, err := v.JobName(glueJob)
+	if err != nil {
+		return apierrors.NewInvalid(GroupVersion.WithKind("GlueJob").GroupKind(), glueJob.Name, field.ErrorList{
+			field.Invalid(field.NewPath("spec", "name"), glueJob.Spec.Name, err.Error()),
+		})
+	}
+	if oldGlueJob != nil {
+		oldName, err := v.JobName(oldGlueJob)
+		if err == nil && oldName == name {
+			return nil
+		}
+	}
+	glueJobs := &GlueJobList{}
+	err = v.List(ctx, glueJobs, client.MatchingFields{GlueJobNameIndex: name})
+	if err != nil {
+		return apierrors.NewInternalError(fmt.Errorf("failed to list GlueJobs: %w", err))
+	}
+	for i := range glueJobs.Items {
+		other := &glueJobs.Items[i]
+		if other.Namespace == glueJob.Namespace && other.Name == glueJob.Name {
+			continue
+		}
+		if oldGlueJob != nil && !other.CreatedBefore(glueJob) {
+			// the other GlueJob gets Conflict condition instead
+			continue
+		}
+		return apierrors.NewInvalid(GroupVersion.WithKind("GlueJob").GroupKind(), glueJob.Name, field.ErrorList{
+			field.Duplicate(field.NewPath("spec", "name"),
+				fmt.Sprintf("%s is already used by GlueJob %s/%s", name, other.Namespace, other.Name)),
+		})
+	}
+	return nil
+}
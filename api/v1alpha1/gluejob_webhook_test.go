@@ -0,0 +1,134 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testJobName will return spec.name or namespace and name of GlueJob
+func testJobName(glueJob *GlueJob) (string, error) {
+	if glueJob.Spec.Name != "" {
+		return glueJob.Spec.Name, nil
+	}
+	return glueJob.Namespace + "-" + glueJob.Name, nil
+}
+
+// newTestGlueJob will return GlueJob created at the time with Glue Job name
+func newTestGlueJob(name string, created time.Time, jobName string) *GlueJob {
+	return &GlueJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "team-a",
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: GlueJobSpec{Name: jobName},
+	}
+}
+
+// newTestValidator will return validator with GlueJobs indexed by Glue Job name
+func newTestValidator(t *testing.T, glueJobs ...client.Object) *GlueJobValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	reader := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(glueJobs...).
+		WithIndex(&GlueJob{}, GlueJobNameIndex, func(obj client.Object) []string {
+			name, _ := testJobName(obj.(*GlueJob))
+			return []string{name}
+		}).
+		Build()
+	return &GlueJobValidator{Reader: reader, JobName: testJobName}
+}
+
+func TestValidateCreate(t *testing.T) {
+	now := time.Now()
+	validator := newTestValidator(t, newTestGlueJob("etl", now, "shared"))
+	if _, err := validator.ValidateCreate(context.Background(), newTestGlueJob("other", now, "unique")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := validator.ValidateCreate(context.Background(), newTestGlueJob("dup", now, "shared")); err == nil {
+		t.Error("expected error of duplicate name")
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	now := time.Now()
+	older := newTestGlueJob("older", now.Add(-time.Hour), "shared")
+	newer := newTestGlueJob("newer", now, "shared")
+	tests := []struct {
+		name   string
+		old    *GlueJob
+		modify func(*GlueJob)
+		valid  bool
+	}{
+		{
+			name:   "older duplicate adds finalizer",
+			old:    older,
+			modify: func(gj *GlueJob) { gj.Finalizers = append(gj.Finalizers, "finalizer") },
+			valid:  true,
+		},
+		{
+			name:   "newer duplicate removes finalizer",
+			old:    newer,
+			modify: func(gj *GlueJob) { gj.Finalizers = nil },
+			valid:  true,
+		},
+		{
+			name:   "newer duplicate changes spec keeping the name",
+			old:    newer,
+			modify: func(gj *GlueJob) { gj.Spec.Description = "changed" },
+			valid:  true,
+		},
+		{
+			name: "newer duplicate is deleted",
+			old:  newer,
+			modify: func(gj *GlueJob) {
+				deleted := metav1.NewTime(now)
+				gj.DeletionTimestamp = &deleted
+				gj.Spec.Name = "another"
+			},
+			valid: true,
+		},
+		{
+			name:   "GlueJob is renamed to unused name",
+			old:    newTestGlueJob("unique", now, "unique"),
+			modify: func(gj *GlueJob) { gj.Spec.Name = "renamed" },
+			valid:  true,
+		},
+		{
+			name:   "GlueJob is renamed to name of older GlueJob",
+			old:    newTestGlueJob("latest", now.Add(time.Hour), "unique"),
+			modify: func(gj *GlueJob) { gj.Spec.Name = "shared" },
+		},
+		{
+			name:   "GlueJob is renamed to name of newer GlueJob",
+			old:    newTestGlueJob("earliest", now.Add(-2*time.Hour), "unique"),
+			modify: func(gj *GlueJob) { gj.Spec.Name = "shared" },
+			valid:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			glueJobs := []client.Object{older.DeepCopy(), newer.DeepCopy()}
+			if tt.old != older && tt.old != newer {
+				glueJobs = append(glueJobs, tt.old.DeepCopy())
+			}
+			validator := newTestValidator(t, glueJobs...)
+			glueJob := tt.old.DeepCopy()
+			tt.modify(glueJob)
+			_, err := validator.ValidateUpdate(context.Background(), tt.old, glueJob)
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ScriptSourceIndex is the field index of GlueJobs by the name of referenced GlueScriptSource
+	ScriptSourceIndex = "scriptSourceRef"
+)
+
+// GlueScriptSourceSpec defines Git repository with scripts of GlueJobs
+type GlueScriptSourceSpec struct {
+	// URL is the URL of Git repository, e.g. https://github.com/org/repo.git or ssh://git@github.com/org/repo.git
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Pattern=`^(https|ssh|git)://.+$|^[A-Za-z0-9._-]+@[^:/]+:.+$`
+	URL string `json:"url"`
+
+	// Ref is the branch or tag of the repository
+	// +kubebuilder:default=main
+	Ref string `json:"ref,omitempty"`
+
+	// Path is the directory of the repository with scripts, defaults to the root of the repository
+	Path string `json:"path,omitempty"`
+
+	// SecretRef is the Secret in the namespace of GlueScriptSource with credentials of the repository:
+	// username and password (e.g. access token) for HTTPS, identity (SSH private key) and known_hosts for SSH
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// Interval is how often the repository is polled for new commits, at least 30s
+	// +kubebuilder:default="5m"
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('30s')",message="interval must be at least 30s"
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// GlueScriptSourceStatus defines the observed state of GlueScriptSource
+type GlueScriptSourceStatus struct {
+	// Commit is the SHA of the last fetched commit
+	Commit string `json:"commit,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the commit was fetched for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions store the status conditions of GlueScriptSource
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// GlueScriptSource is the Schema for the gluescriptsources API
+type GlueScriptSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GlueScriptSourceSpec   `json:"spec,omitempty"`
+	Status GlueScriptSourceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GlueScriptSourceList contains a list of GlueScriptSource
+type GlueScriptSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlueScriptSource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GlueScriptSource{}, &GlueScriptSourceList{})
+}
@@ -21,10 +21,166 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSAssumeRole) DeepCopyInto(out *AWSAssumeRole) {
+	*out = *in
+	if in.SessionTags != nil {
+		in, out := &in.SessionTags, &out.SessionTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSAssumeRole.
+func (in *AWSAssumeRole) DeepCopy() *AWSAssumeRole {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSAssumeRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSEndpoints) DeepCopyInto(out *AWSEndpoints) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSEndpoints.
+func (in *AWSEndpoints) DeepCopy() *AWSEndpoints {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSEndpoints)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSProviderConfig) DeepCopyInto(out *AWSProviderConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSProviderConfig.
+func (in *AWSProviderConfig) DeepCopy() *AWSProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSProviderConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSProviderConfigList) DeepCopyInto(out *AWSProviderConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AWSProviderConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSProviderConfigList.
+func (in *AWSProviderConfigList) DeepCopy() *AWSProviderConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSProviderConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AWSProviderConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSProviderConfigSpec) DeepCopyInto(out *AWSProviderConfigSpec) {
+	*out = *in
+	if in.AssumeRole != nil {
+		in, out := &in.AssumeRole, &out.AssumeRole
+		*out = new(AWSAssumeRole)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebIdentity != nil {
+		in, out := &in.WebIdentity, &out.WebIdentity
+		*out = new(AWSWebIdentity)
+		**out = **in
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = new(AWSEndpoints)
+		**out = **in
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedAssumeRoles != nil {
+		in, out := &in.AllowedAssumeRoles, &out.AllowedAssumeRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSProviderConfigSpec.
+func (in *AWSProviderConfigSpec) DeepCopy() *AWSProviderConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSProviderConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSWebIdentity) DeepCopyInto(out *AWSWebIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSWebIdentity.
+func (in *AWSWebIdentity) DeepCopy() *AWSWebIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSWebIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlueJob) DeepCopyInto(out *GlueJob) {
 	*out = *in
@@ -52,9 +208,79 @@ func (in *GlueJob) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobAWSSecret) DeepCopyInto(out *GlueJobAWSSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobAWSSecret.
+func (in *GlueJobAWSSecret) DeepCopy() *GlueJobAWSSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobAWSSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobArgumentValueFrom) DeepCopyInto(out *GlueJobArgumentValueFrom) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobArgumentValueFrom.
+func (in *GlueJobArgumentValueFrom) DeepCopy() *GlueJobArgumentValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobArgumentValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobArgumentsFromSource) DeepCopyInto(out *GlueJobArgumentsFromSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobArgumentsFromSource.
+func (in *GlueJobArgumentsFromSource) DeepCopy() *GlueJobArgumentsFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobArgumentsFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlueJobCommand) DeepCopyInto(out *GlueJobCommand) {
 	*out = *in
+	if in.ScriptSource != nil {
+		in, out := &in.ScriptSource, &out.ScriptSource
+		*out = new(GlueJobScriptSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobCommand.
@@ -82,6 +308,21 @@ func (in *GlueJobExecutionProperty) DeepCopy() *GlueJobExecutionProperty {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobGitScriptRef) DeepCopyInto(out *GlueJobGitScriptRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobGitScriptRef.
+func (in *GlueJobGitScriptRef) DeepCopy() *GlueJobGitScriptRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobGitScriptRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlueJobList) DeepCopyInto(out *GlueJobList) {
 	*out = *in
@@ -114,10 +355,247 @@ func (in *GlueJobList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobModulesConfigMap) DeepCopyInto(out *GlueJobModulesConfigMap) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobModulesConfigMap.
+func (in *GlueJobModulesConfigMap) DeepCopy() *GlueJobModulesConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobModulesConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobNotificationProperty) DeepCopyInto(out *GlueJobNotificationProperty) {
+	*out = *in
+	if in.NotifyDelayAfter != nil {
+		in, out := &in.NotifyDelayAfter, &out.NotifyDelayAfter
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobNotificationProperty.
+func (in *GlueJobNotificationProperty) DeepCopy() *GlueJobNotificationProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobNotificationProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobPlacement) DeepCopyInto(out *GlueJobPlacement) {
+	*out = *in
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(ProviderConfigReference)
+		**out = **in
+	}
+	if in.AssumeRole != nil {
+		in, out := &in.AssumeRole, &out.AssumeRole
+		*out = new(AWSAssumeRole)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultArguments != nil {
+		in, out := &in.DefaultArguments, &out.DefaultArguments
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobPlacement.
+func (in *GlueJobPlacement) DeepCopy() *GlueJobPlacement {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobPlacement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobPlacementStatus) DeepCopyInto(out *GlueJobPlacementStatus) {
+	*out = *in
+	if in.PlannedChanges != nil {
+		in, out := &in.PlannedChanges, &out.PlannedChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MissingArtifacts != nil {
+		in, out := &in.MissingArtifacts, &out.MissingArtifacts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Placement.DeepCopyInto(&out.Placement)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobPlacementStatus.
+func (in *GlueJobPlacementStatus) DeepCopy() *GlueJobPlacementStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobPlacementStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobPlan) DeepCopyInto(out *GlueJobPlan) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobPlan.
+func (in *GlueJobPlan) DeepCopy() *GlueJobPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobPythonModules) DeepCopyInto(out *GlueJobPythonModules) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]GlueJobModulesConfigMap, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobPythonModules.
+func (in *GlueJobPythonModules) DeepCopy() *GlueJobPythonModules {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobPythonModules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobRay) DeepCopyInto(out *GlueJobRay) {
+	*out = *in
+	if in.PipInstall != nil {
+		in, out := &in.PipInstall, &out.PipInstall
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.S3PyModules != nil {
+		in, out := &in.S3PyModules, &out.S3PyModules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinWorkers != nil {
+		in, out := &in.MinWorkers, &out.MinWorkers
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobRay.
+func (in *GlueJobRay) DeepCopy() *GlueJobRay {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobRay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobScriptSource) DeepCopyInto(out *GlueJobScriptSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitRef != nil {
+		in, out := &in.GitRef, &out.GitRef
+		*out = new(GlueJobGitScriptRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobScriptSource.
+func (in *GlueJobScriptSource) DeepCopy() *GlueJobScriptSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobScriptSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobSmokeTest) DeepCopyInto(out *GlueJobSmokeTest) {
+	*out = *in
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobSmokeTest.
+func (in *GlueJobSmokeTest) DeepCopy() *GlueJobSmokeTest {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobSmokeTest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueJobSourceControl) DeepCopyInto(out *GlueJobSourceControl) {
+	*out = *in
+	if in.AuthTokenSecretRef != nil {
+		in, out := &in.AuthTokenSecretRef, &out.AuthTokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobSourceControl.
+func (in *GlueJobSourceControl) DeepCopy() *GlueJobSourceControl {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueJobSourceControl)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlueJobSpec) DeepCopyInto(out *GlueJobSpec) {
 	*out = *in
-	out.Command = in.Command
+	in.Command.DeepCopyInto(&out.Command)
 	if in.ExecutionProperty != nil {
 		in, out := &in.ExecutionProperty, &out.ExecutionProperty
 		*out = new(GlueJobExecutionProperty)
@@ -130,6 +608,25 @@ func (in *GlueJobSpec) DeepCopyInto(out *GlueJobSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.DefaultArgumentsFrom != nil {
+		in, out := &in.DefaultArgumentsFrom, &out.DefaultArgumentsFrom
+		*out = make([]GlueJobArgumentsFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultArgumentsValueFrom != nil {
+		in, out := &in.DefaultArgumentsValueFrom, &out.DefaultArgumentsValueFrom
+		*out = make(map[string]GlueJobArgumentValueFrom, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.AWSSecrets != nil {
+		in, out := &in.AWSSecrets, &out.AWSSecrets
+		*out = make([]GlueJobAWSSecret, len(*in))
+		copy(*out, *in)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -137,6 +634,55 @@ func (in *GlueJobSpec) DeepCopyInto(out *GlueJobSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Ray != nil {
+		in, out := &in.Ray, &out.Ray
+		*out = new(GlueJobRay)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotificationProperty != nil {
+		in, out := &in.NotificationProperty, &out.NotificationProperty
+		*out = new(GlueJobNotificationProperty)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NonOverridableArguments != nil {
+		in, out := &in.NonOverridableArguments, &out.NonOverridableArguments
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ProviderConfigRef != nil {
+		in, out := &in.ProviderConfigRef, &out.ProviderConfigRef
+		*out = new(ProviderConfigReference)
+		**out = **in
+	}
+	if in.PythonModules != nil {
+		in, out := &in.PythonModules, &out.PythonModules
+		*out = new(GlueJobPythonModules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SourceControlDetails != nil {
+		in, out := &in.SourceControlDetails, &out.SourceControlDetails
+		*out = new(GlueJobSourceControl)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SmokeTest != nil {
+		in, out := &in.SmokeTest, &out.SmokeTest
+		*out = new(GlueJobSmokeTest)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placements != nil {
+		in, out := &in.Placements, &out.Placements
+		*out = make([]GlueJobPlacement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueJobSpec.
@@ -152,6 +698,18 @@ func (in *GlueJobSpec) DeepCopy() *GlueJobSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlueJobStatus) DeepCopyInto(out *GlueJobStatus) {
 	*out = *in
+	if in.PendingPlan != nil {
+		in, out := &in.PendingPlan, &out.PendingPlan
+		*out = new(GlueJobPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placements != nil {
+		in, out := &in.Placements, &out.Placements
+		*out = make([]GlueJobPlacementStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -170,3 +728,120 @@ func (in *GlueJobStatus) DeepCopy() *GlueJobStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueScriptSource) DeepCopyInto(out *GlueScriptSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueScriptSource.
+func (in *GlueScriptSource) DeepCopy() *GlueScriptSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueScriptSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlueScriptSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueScriptSourceList) DeepCopyInto(out *GlueScriptSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GlueScriptSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueScriptSourceList.
+func (in *GlueScriptSourceList) DeepCopy() *GlueScriptSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueScriptSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlueScriptSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueScriptSourceSpec) DeepCopyInto(out *GlueScriptSourceSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueScriptSourceSpec.
+func (in *GlueScriptSourceSpec) DeepCopy() *GlueScriptSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueScriptSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlueScriptSourceStatus) DeepCopyInto(out *GlueScriptSourceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlueScriptSourceStatus.
+func (in *GlueScriptSourceStatus) DeepCopy() *GlueScriptSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GlueScriptSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderConfigReference) DeepCopyInto(out *ProviderConfigReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigReference.
+func (in *ProviderConfigReference) DeepCopy() *ProviderConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+)
+
+// planHashLength is the number of hex characters of plan hash
+const planHashLength = 16
+
+// reviewPlan will compute changes of GlueJob, which require approval, and return true if
+// there are none or they are approved by the approved plan annotation. Otherwise the plan is
+// written to status as pending. All approvals and rejected approvals are recorded as events
+func (r *GlueJobReconciler) reviewPlan(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob, jobs []placementGlueJob,
+	policy string) (bool, error) {
+	changes, err := planPlacements(jobs, policy)
+	if err != nil {
+		return false, err
+	}
+	if len(changes) == 0 {
+		gj.Status.PendingPlan = nil
+		return true, nil
+	}
+	hash, err := planHash(jobs, changes)
+	if err != nil {
+		return false, err
+	}
+
+	approval := gj.Annotations[awsv1alpha1.ApprovedPlanAnnotation]
+	if approval == hash {
+		reqLogger.V(0).Info("Applying approved plan", "hash", hash, "changes", changes)
+		r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonPlanApproved,
+			"Applying approved plan %s: %s", hash, strings.Join(changes, "; "))
+		gj.Status.PendingPlan = nil
+		return true, nil
+	}
+
+	pending := gj.Status.PendingPlan == nil || gj.Status.PendingPlan.Hash != hash
+	gj.Status.PendingPlan = &awsv1alpha1.GlueJobPlan{
+		Hash:    hash,
+		Changes: changes,
+	}
+	if approval != "" && approval != gj.Status.AppliedPlanHash {
+		// approval of an older plan, the spec or Glue Job on AWS changed since
+		reqLogger.V(0).Info("Rejecting stale approval", "approval", approval, "hash", hash)
+		r.Recorder.Eventf(gj, corev1.EventTypeWarning, consts.ReasonApprovalRejected,
+			"Approval %s doesn't match pending plan %s, review the plan and approve it again", approval, hash)
+	}
+	if pending {
+		r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonPlanPending,
+			"Plan %s awaits approval: %s", hash, strings.Join(changes, "; "))
+	}
+	return false, nil
+}
+
+// planPlacements will return mutations of Glue Job in all placements, which are allowed by policy
+func planPlacements(jobs []placementGlueJob, policy string) ([]string, error) {
+	changes := make([]string, 0)
+	add := func(placement string, planned []string) {
+		for _, change := range planned {
+			changes = append(changes, fmt.Sprintf("%s: %s", placement, change))
+		}
+	}
+
+	locations := make(map[string]struct{}, len(jobs))
+	for i := range jobs {
+		pj := &jobs[i]
+		if pj.removed {
+			continue
+		}
+		if pj.err != nil {
+			return nil, fmt.Errorf("failed to plan placement %s: %w", pj.placement.Name, pj.err)
+		}
+		locations[pj.status.AccountID+"/"+pj.status.Region] = struct{}{}
+		if pj.job.OwnershipConflict() != nil {
+			// never mutated, conflict is reported by reconcile
+			continue
+		}
+		if policy == awsv1alpha1.ManagementPolicyCreateOnly && pj.job.JobExists() {
+			continue
+		}
+		add(pj.placement.Name, pj.job.Plan())
+	}
+	if policy != awsv1alpha1.ManagementPolicyFull {
+		return changes, nil
+	}
+
+	// Glue Jobs of removed placements are deleted
+	for i := range jobs {
+		pj := &jobs[i]
+		if !pj.removed {
+			continue
+		}
+		if pj.err != nil {
+			return nil, fmt.Errorf("failed to plan removed placement %s: %w", pj.status.Name, pj.err)
+		}
+		if _, ok := locations[pj.status.AccountID+"/"+pj.status.Region]; ok || pj.job.OwnershipConflict() != nil {
+			continue
+		}
+		add(pj.status.Name, pj.job.DeletePlan())
+	}
+	return changes, nil
+}
+
+// planHash will return hash of planned changes and of the definition and tags of Glue Job in every
+// placement, including resolved script locations and argument values, so approval of the plan
+// becomes stale once anything applied to AWS changes
+func planHash(jobs []placementGlueJob, changes []string) (string, error) {
+	hash := sha256.New()
+	for _, change := range changes {
+		hash.Write([]byte(change + "\n"))
+	}
+	for i := range jobs {
+		pj := &jobs[i]
+		if pj.removed || pj.job == nil {
+			continue
+		}
+		desired, err := pj.job.DesiredState()
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte(pj.placement.Name + "\n"))
+		hash.Write(desired)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:planHashLength], nil
+}
+
+// setAwaitingApprovalCondition will set Synced condition of GlueJob with pending plan
+func setAwaitingApprovalCondition(gj *awsv1alpha1.GlueJob) {
+	plan := gj.Status.PendingPlan
+	meta.SetStatusCondition(&gj.Status.Conditions, metav1.Condition{
+		Type:   consts.StatusSynced,
+		Status: metav1.ConditionFalse,
+		Reason: consts.ReasonAwaitingApproval,
+		Message: fmt.Sprintf("plan %s with %d changes awaits approval, set annotation %s=%s",
+			plan.Hash, len(plan.Changes), awsv1alpha1.ApprovedPlanAnnotation, plan.Hash),
+	})
+}
+
+// approvalHeld will return why changes of GlueJob are held until approval
+func approvalHeld(gj *awsv1alpha1.GlueJob) string {
+	return fmt.Sprintf("pending approval of plan %s", gj.Status.PendingPlan.Hash)
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+)
+
+func TestPlanPlacementsFailed(t *testing.T) {
+	jobs := []placementGlueJob{{
+		placement: &awsv1alpha1.GlueJobPlacement{Name: "primary"},
+		err:       errors.New("AWSProviderConfig isn't allowed"),
+	}}
+	if _, err := planPlacements(jobs, awsv1alpha1.ManagementPolicyFull); err == nil {
+		t.Error("expected error of placement, which Glue Job couldn't be built")
+	}
+}
+
+func TestPlanPlacementsNoDelete(t *testing.T) {
+	jobs := []placementGlueJob{{
+		status:  awsv1alpha1.GlueJobPlacementStatus{Name: "old", AccountID: "123456789012", Region: "eu-west-1"},
+		removed: true,
+	}}
+	jobs[0].placement = &jobs[0].status.Placement
+	changes, err := planPlacements(jobs, awsv1alpha1.ManagementPolicyNoDelete)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("expected no deletion planned by NoDelete policy, got %v: %v", changes, err)
+	}
+}
+
+func TestPlanHash(t *testing.T) {
+	changes := []string{"primary: UpdateJob: role"}
+	hash, err := planHash(nil, changes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hash) != planHashLength {
+		t.Errorf("expected hash of %d characters, got %s", planHashLength, hash)
+	}
+	again, _ := planHash(nil, []string{"primary: UpdateJob: role"})
+	if again != hash {
+		t.Errorf("expected stable hash %s, got %s", hash, again)
+	}
+	other, _ := planHash(nil, []string{"primary: UpdateJob: role, timeout"})
+	if other == hash {
+		t.Error("expected hash to change with changes")
+	}
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+)
+
+// resolveDefaultArguments will merge default arguments from ConfigMaps and Secrets into the default arguments
+// of GlueJob. Keys of defaultArgumentsFrom are overridden by defaultArguments, which are overridden by
+// defaultArgumentsValueFrom
+func (r *GlueJobReconciler) resolveDefaultArguments(gj *awsv1alpha1.GlueJob) error {
+	// arguments aren't needed to delete Glue Job, their ConfigMaps and Secrets may be deleted already
+	if len(gj.Spec.DefaultArgumentsFrom) == 0 && len(gj.Spec.DefaultArgumentsValueFrom) == 0 ||
+		!gj.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	args := make(map[string]string)
+	for _, source := range gj.Spec.DefaultArgumentsFrom {
+		data, err := r.argumentsSourceData(gj.Namespace, source)
+		if err != nil {
+			return err
+		}
+		for key, value := range data {
+			args[source.Prefix+key] = value
+		}
+	}
+	for key, value := range gj.Spec.DefaultArguments {
+		args[key] = value
+	}
+	for key, valueFrom := range gj.Spec.DefaultArgumentsValueFrom {
+		value, ok, err := r.argumentValue(gj.Namespace, valueFrom)
+		if err != nil {
+			return fmt.Errorf("default argument %s: %w", key, err)
+		}
+		if ok {
+			args[key] = value
+		}
+	}
+	gj.Spec.DefaultArguments = args
+	return nil
+}
+
+// argumentsSourceData will return the keys of ConfigMap or Secret of defaultArgumentsFrom
+func (r *GlueJobReconciler) argumentsSourceData(namespace string,
+	source awsv1alpha1.GlueJobArgumentsFromSource) (map[string]string, error) {
+	data := make(map[string]string)
+	if source.ConfigMapRef != nil {
+		configMap := &corev1.ConfigMap{}
+		err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: source.ConfigMapRef.Name}, configMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ConfigMap %s with default arguments: %w", source.ConfigMapRef.Name, err)
+		}
+		for key, value := range configMap.Data {
+			data[key] = value
+		}
+		return data, nil
+	}
+	if source.SecretRef != nil {
+		secret := &corev1.Secret{}
+		err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: source.SecretRef.Name}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s with default arguments: %w", source.SecretRef.Name, err)
+		}
+		for key, value := range secret.Data {
+			data[key] = string(value)
+		}
+	}
+	return data, nil
+}
+
+// argumentValue will return the value of default argument from the key of ConfigMap or Secret,
+// false is returned if optional key or its object doesn't exist
+func (r *GlueJobReconciler) argumentValue(namespace string,
+	valueFrom awsv1alpha1.GlueJobArgumentValueFrom) (string, bool, error) {
+	if ref := valueFrom.ConfigMapKeyRef; ref != nil {
+		optional := ref.Optional != nil && *ref.Optional
+		configMap := &corev1.ConfigMap{}
+		err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap)
+		if err != nil {
+			if errors.IsNotFound(err) && optional {
+				return "", false, nil
+			}
+			return "", false, fmt.Errorf("failed to get ConfigMap %s: %w", ref.Name, err)
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok && !optional {
+			return "", false, fmt.Errorf("ConfigMap %s has no key %s", ref.Name, ref.Key)
+		}
+		return value, ok, nil
+	}
+	if ref := valueFrom.SecretKeyRef; ref != nil {
+		optional := ref.Optional != nil && *ref.Optional
+		secret := &corev1.Secret{}
+		err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) && optional {
+				return "", false, nil
+			}
+			return "", false, fmt.Errorf("failed to get Secret %s: %w", ref.Name, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok && !optional {
+			return "", false, fmt.Errorf("Secret %s has no key %s", ref.Name, ref.Key)
+		}
+		return string(value), ok, nil
+	}
+	return "", false, nil
+}
+
+// setPlaintextSecretsCondition will set PlaintextSecrets condition, when default arguments get values
+// from Secrets, as Glue stores them in plaintext. Condition is persisted with the next status update
+func setPlaintextSecretsCondition(gj *awsv1alpha1.GlueJob) {
+	sources := make([]string, 0)
+	for _, source := range gj.Spec.DefaultArgumentsFrom {
+		if source.SecretRef != nil {
+			sources = append(sources, fmt.Sprintf("keys of Secret %s", source.SecretRef.Name))
+		}
+	}
+	args := make([]string, 0)
+	for key, valueFrom := range gj.Spec.DefaultArgumentsValueFrom {
+		if valueFrom.SecretKeyRef != nil {
+			args = append(args, key)
+		}
+	}
+	sort.Strings(args)
+	if len(args) > 0 {
+		sources = append(sources, fmt.Sprintf("arguments %s", strings.Join(args, ", ")))
+	}
+	if len(sources) == 0 {
+		meta.RemoveStatusCondition(&gj.Status.Conditions, consts.StatusPlaintextSecrets)
+		return
+	}
+	meta.SetStatusCondition(&gj.Status.Conditions, metav1.Condition{
+		Type:   consts.StatusPlaintextSecrets,
+		Status: metav1.ConditionTrue,
+		Reason: consts.ReasonSecretArguments,
+		Message: fmt.Sprintf("Secret values of %s are stored in plaintext default arguments of Glue Job",
+			strings.Join(sources, " and ")),
+	})
+}
+
+// secretNames will return the names of Secrets with default arguments of GlueJob
+func secretNames(gj *awsv1alpha1.GlueJob) []string {
+	names := make(map[string]struct{})
+	for _, source := range gj.Spec.DefaultArgumentsFrom {
+		if source.SecretRef != nil {
+			names[source.SecretRef.Name] = struct{}{}
+		}
+	}
+	for _, valueFrom := range gj.Spec.DefaultArgumentsValueFrom {
+		if valueFrom.SecretKeyRef != nil {
+			names[valueFrom.SecretKeyRef.Name] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// glueJobsForSecret will return requests for GlueJobs, which default arguments are in Secret
+func (r *GlueJobReconciler) glueJobsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := r.List(ctx, glueJobs, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{awsv1alpha1.SecretIndex: obj.GetName()})
+	if err != nil {
+		log.FromContext(ctx).V(0).Error(err, "Failed to list GlueJobs referencing Secret",
+			"namespace", obj.GetNamespace(), "secret", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(glueJobs.Items))
+	for i := range glueJobs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&glueJobs.Items[i])})
+	}
+	return requests
+}
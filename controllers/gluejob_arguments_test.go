@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+)
+
+func TestResolveDefaultArguments(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "args"},
+		Data:       map[string]string{"env": "from-configmap", "region": "eu-west-1", "db": "from-configmap"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "creds"},
+		Data:       map[string][]byte{"password": []byte("secret"), "db": []byte("from-secret")},
+	}
+	// ConfigMaps and Secrets are read bypassing the cache, so the reconciler has no cached client
+	r := &GlueJobReconciler{
+		ctx:       context.Background(),
+		APIReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap, secret).Build(),
+	}
+	configMapKey := func(name, key string, optional bool) awsv1alpha1.GlueJobArgumentValueFrom {
+		return awsv1alpha1.GlueJobArgumentValueFrom{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key, Optional: &optional}}
+	}
+	secretKey := func(name, key string, optional bool) awsv1alpha1.GlueJobArgumentValueFrom {
+		return awsv1alpha1.GlueJobArgumentValueFrom{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key, Optional: &optional}}
+	}
+
+	tests := []struct {
+		name      string
+		from      []awsv1alpha1.GlueJobArgumentsFromSource
+		arguments map[string]string
+		valueFrom map[string]awsv1alpha1.GlueJobArgumentValueFrom
+		expected  map[string]string
+		wantErr   bool
+	}{
+		{
+			name: "later sources override earlier ones",
+			from: []awsv1alpha1.GlueJobArgumentsFromSource{
+				{Prefix: "--", ConfigMapRef: &corev1.LocalObjectReference{Name: "args"}},
+				{Prefix: "--", SecretRef: &corev1.LocalObjectReference{Name: "creds"}},
+			},
+			expected: map[string]string{
+				"--env": "from-configmap", "--region": "eu-west-1", "--db": "from-secret", "--password": "secret",
+			},
+		},
+		{
+			name:      "defaultArguments override defaultArgumentsFrom",
+			from:      []awsv1alpha1.GlueJobArgumentsFromSource{{ConfigMapRef: &corev1.LocalObjectReference{Name: "args"}}},
+			arguments: map[string]string{"env": "inline"},
+			expected:  map[string]string{"env": "inline", "region": "eu-west-1", "db": "from-configmap"},
+		},
+		{
+			name:      "defaultArgumentsValueFrom override defaultArguments",
+			arguments: map[string]string{"--env": "inline", "--job-language": "python"},
+			valueFrom: map[string]awsv1alpha1.GlueJobArgumentValueFrom{
+				"--env":      configMapKey("args", "env", false),
+				"--password": secretKey("creds", "password", false),
+			},
+			expected: map[string]string{"--env": "from-configmap", "--job-language": "python", "--password": "secret"},
+		},
+		{
+			name:      "optional missing key and object are skipped",
+			arguments: map[string]string{"--env": "inline"},
+			valueFrom: map[string]awsv1alpha1.GlueJobArgumentValueFrom{
+				"--env":   configMapKey("args", "missing", true),
+				"--token": secretKey("missing", "token", true),
+			},
+			expected: map[string]string{"--env": "inline"},
+		},
+		{
+			name:      "required missing key",
+			valueFrom: map[string]awsv1alpha1.GlueJobArgumentValueFrom{"--env": configMapKey("args", "missing", false)},
+			wantErr:   true,
+		},
+		{
+			name:      "required missing object",
+			valueFrom: map[string]awsv1alpha1.GlueJobArgumentValueFrom{"--token": secretKey("missing", "token", false)},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gj := &awsv1alpha1.GlueJob{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "etl"}}
+			gj.Spec.DefaultArgumentsFrom = tt.from
+			gj.Spec.DefaultArguments = tt.arguments
+			gj.Spec.DefaultArgumentsValueFrom = tt.valueFrom
+			err := r.resolveDefaultArguments(gj)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gj.Spec.DefaultArguments, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, gj.Spec.DefaultArguments)
+			}
+		})
+	}
+}
@@ -19,25 +19,40 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
 	"github.com/90poe/glue-jobs-operator/internal/config"
 	"github.com/90poe/glue-jobs-operator/internal/consts"
+	"github.com/90poe/glue-jobs-operator/internal/gitsource"
 	"github.com/90poe/glue-jobs-operator/internal/glue"
+	"github.com/90poe/glue-jobs-operator/internal/naming"
 	"github.com/go-logr/logr"
 )
 
@@ -45,15 +60,34 @@ const glueJobFinalizer = "gluejobs.aws.90poe.io/finalizer"
 
 // GlueJobReconciler reconciles a GlueJob object
 type GlueJobReconciler struct {
-	ctx    context.Context
-	config config.OperatorConfig
+	ctx context.Context
 	client.Client
 	Scheme *runtime.Scheme
+	// Config holds the operator config, which is reloaded on changes of the config file
+	Config *config.Store
+	// AWS provides AWS clients for GlueJobs
+	AWS *awsclient.Provider
+	// Recorder records events of GlueJobs
+	Recorder record.EventRecorder
+	// APIReader reads leader election leases of other shards and referenced ConfigMaps and Secrets bypassing the cache,
+	// only metadata of ConfigMaps and Secrets is cached to find GlueJobs referencing them
+	APIReader client.Reader
+	// ScriptSources are the files of GlueScriptSources fetched by GlueScriptSourceReconciler
+	ScriptSources *gitsource.Cache
+	// LeaseNamespace is the namespace of leader election leases of shards
+	LeaseNamespace string
+	shardMu        sync.Mutex
+	// shardOwned are GlueJobs reconciled by the shard of this instance
+	shardOwned map[types.NamespacedName]struct{}
 }
 
 //+kubebuilder:rbac:groups=aws.90poe.io,resources=gluejobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=aws.90poe.io,resources=gluejobs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=aws.90poe.io,resources=gluejobs/finalizers,verbs=update
+//+kubebuilder:rbac:groups=aws.90poe.io,resources=awsproviderconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -63,15 +97,27 @@ func (r *GlueJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 	r.ctx = ctx
 	reqLogger := log.FromContext(ctx).WithValues("gluejobs", req.NamespacedName)
 
+	// GlueJobs in namespaces not watched by the operator are left to other instances of the operator
+	watched, err := r.Config.Get().WatchesNamespace(ctx, r, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !watched {
+		reqLogger.V(1).Info("GlueJob namespace isn't watched. Ignoring.")
+		return ctrl.Result{}, nil
+	}
+
 	// Fetch the GlueJob K8S object instance
 	glueJob := &awsv1alpha1.GlueJob{}
-	err := r.Get(ctx, req.NamespacedName, glueJob)
+	err = r.Get(ctx, req.NamespacedName, glueJob)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
 			reqLogger.V(1).Info("GlueJob resource not found. Ignoring since object must be deleted.")
+			r.setShardOwned(req.NamespacedName, false)
+			r.AWS.ForgetPlacements(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -79,85 +125,323 @@ func (r *GlueJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_
 		return ctrl.Result{}, err
 	}
 
-	// 1. check if job exists on AWS
-	// 2. if exists, update job up to date
-	// 3. if not exists, create job on AWS
+	// GlueJobs are split between shards, GlueJob is reconciled by single shard only
+	owned, result, err := r.reconcileShard(reqLogger, glueJob)
+	if err != nil || !owned {
+		return result, err
+	}
 
-	// 1. check if job exists on AWS
-	awsGlueJob, err := glue.NewJob(ctx, glueJob.Spec)
+	// Paused GlueJob is left untouched, including finalization, until the annotation is removed
+	if r.setPausedCondition(reqLogger, glueJob) {
+		return ctrl.Result{}, r.Status().Update(ctx, glueJob)
+	}
+
+	// Glue Job name is either spec.name or derived from GlueJob namespace and name
+	jobName, err := naming.JobName(r.Config.Get(), glueJob)
 	if err != nil {
-		return r.setLatestError(glueJob, err, "CreateNewGlueJobFailed")
+		return r.setLatestError(glueJob, err, "InvalidGlueJobName")
+	}
+	glueJob.Status.ResolvedName = jobName
+
+	// Glue Job name must be unique, only the earliest GlueJob using the name manages Glue Job
+	duplicateErr := r.checkDuplicateName(glueJob)
+	if duplicateErr != nil && !errors.IsConflict(duplicateErr) {
+		return r.setLatestError(glueJob, duplicateErr, "GlueJobFailed")
+	}
+	r.setConflictCondition(glueJob, duplicateErr)
+	setPlaintextSecretsCondition(glueJob)
+	if duplicateErr != nil {
+		if glueJob.GetDeletionTimestamp() != nil {
+			// Glue Job belongs to another GlueJob, just let this one go
+			return ctrl.Result{}, r.addOrRemoveFinalizer(glueJob, false)
+		}
+		return r.setLatestError(glueJob, duplicateErr, consts.StatusConflict)
 	}
 
 	// Check if the GlueJob instance is marked to be deleted, which is
 	// indicated by the deletion timestamp being set.
 	isGlueJobdMarkedToBeDeleted := glueJob.GetDeletionTimestamp() != nil
+	if isGlueJobdMarkedToBeDeleted && !controllerutil.ContainsFinalizer(glueJob, glueJobFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// Glue Jobs of placements are built once for both planning and reconciling
+	jobs := r.placementGlueJobs(glueJob)
+
+	// Changes of GlueJob requiring approval are only planned until the plan is approved
+	policy := r.managementPolicy(glueJob)
+	held := fmt.Sprintf("%s management policy", policy)
+	approved := true
+	if glueJob.Spec.RequireApproval && !isGlueJobdMarkedToBeDeleted && policy != awsv1alpha1.ManagementPolicyObserveOnly {
+		approved, err = r.reviewPlan(reqLogger, glueJob, jobs, policy)
+		if err != nil {
+			return r.setLatestError(glueJob, err, "PlanFailed")
+		}
+		if !approved {
+			policy = awsv1alpha1.ManagementPolicyObserveOnly
+			held = approvalHeld(glueJob)
+		}
+	} else {
+		glueJob.Status.PendingPlan = nil
+	}
+
+	// 1. check if job exists on AWS in every placement
+	// 2. if exists, update job up to date
+	// 3. if not exists, create job on AWS
+	// Glue Jobs of placements removed from the spec are deleted
+	results := r.reconcilePlacements(reqLogger, glueJob, jobs, policy, held, isGlueJobdMarkedToBeDeleted)
+	r.setOwnershipCondition(&glueJob.Status.Conditions, results.conflict)
+	if !approved {
+		setAwaitingApprovalCondition(glueJob)
+	} else if glueJob.Spec.RequireApproval && results.err == nil {
+		// approval is consumed, it's not reported as stale once the spec changes
+		glueJob.Status.AppliedPlanHash = glueJob.Annotations[awsv1alpha1.ApprovedPlanAnnotation]
+	}
+
 	if isGlueJobdMarkedToBeDeleted {
-		if controllerutil.ContainsFinalizer(glueJob, glueJobFinalizer) {
-			// Run finalization logic for GlueJobFinalizer. If the
-			// finalization logic fails, don't remove the finalizer so
-			// that we can retry during the next reconciliation.
-			if err := r.finalizeGlueJob(reqLogger, glueJob, awsGlueJob); err != nil {
-				return ctrl.Result{
-					// requeue after 5 seconds
-					RequeueAfter: 5 * time.Second,
-				}, err
-			}
+		// Run finalization logic for GlueJobFinalizer. If the
+		// finalization logic fails, don't remove the finalizer so
+		// that we can retry during the next reconciliation.
+		if results.err != nil {
+			return ctrl.Result{
+				// requeue after 5 seconds
+				RequeueAfter: 5 * time.Second,
+			}, results.err
+		}
 
-			// Remove GlueJobFinalizer. Once all finalizers have been
-			// removed, the object will be deleted.
-			err = r.addOrRemoveFinalizer(glueJob, false)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
+		// Remove GlueJobFinalizer. Once all finalizers have been
+		// removed, the object will be deleted.
+		err = r.addOrRemoveFinalizer(glueJob, false)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
 
-	message := "Successfully updated GlueJob"
-	if awsGlueJob.JobExists() {
-		// 2. if exists, update job up to date
-		err = r.updateJob(awsGlueJob, reqLogger)
-	} else {
-		// 3. if not exists, create job on AWS
-		err = r.createJob(awsGlueJob, reqLogger)
-		message = "Successfully created GlueJob"
+	// Add finalizer for this CR, once Glue Job exists in any placement
+	if results.applied {
+		err = r.addOrRemoveFinalizer(glueJob, true)
+		if err != nil {
+			return r.setLatestError(glueJob, err, "GlueJobFailed")
+		}
 	}
-	if err != nil {
-		return r.setLatestError(glueJob, err, "GlueJobFailed")
+	if results.err != nil {
+		return r.setLatestError(glueJob, results.err, results.reason)
 	}
-
-	// Add finalizer for this CR
-	err = r.addOrRemoveFinalizer(glueJob, true)
-	if err != nil {
-		return r.setLatestError(glueJob, err, "GlueJobFailed")
+	if len(results.missing) > 0 {
+		return r.missingReconcileRet(glueJob, reqLogger, results.missing)
 	}
 
-	return r.succReconcileRet(glueJob, reqLogger, message)
+	return r.succReconcileRet(glueJob, reqLogger, results.message)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GlueJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	var err error
-	// get config from Env
-	r.config, err = config.New()
+	cfg := r.Config.Get()
+
+	// index GlueJobs by Glue Job name to detect duplicates
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &awsv1alpha1.GlueJob{}, awsv1alpha1.GlueJobNameIndex,
+		func(obj client.Object) []string {
+			glueJob, ok := obj.(*awsv1alpha1.GlueJob)
+			if !ok {
+				return nil
+			}
+			jobName, err := naming.JobName(r.Config.Get(), glueJob)
+			if err != nil {
+				return nil
+			}
+			return []string{jobName}
+		})
 	if err != nil {
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&awsv1alpha1.GlueJob{}).
-		WithOptions(controller.Options{MaxConcurrentReconciles: r.config.MaxConcurrentReconciles}).
-		WithEventFilter(ignoreUpdateDeletePredicate()).
+	// index GlueJobs by referenced AWSProviderConfigs to reconcile them, when they change
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &awsv1alpha1.GlueJob{}, awsv1alpha1.ProviderConfigIndex,
+		func(obj client.Object) []string {
+			glueJob, ok := obj.(*awsv1alpha1.GlueJob)
+			if !ok {
+				return nil
+			}
+			names := make([]string, 0)
+			if glueJob.Spec.ProviderConfigRef != nil {
+				names = append(names, glueJob.Spec.ProviderConfigRef.Name)
+			}
+			for _, placement := range glueJob.Spec.Placements {
+				if placement.ProviderConfigRef != nil {
+					names = append(names, placement.ProviderConfigRef.Name)
+				}
+			}
+			return names
+		})
+	if err != nil {
+		return err
+	}
+
+	// index GlueJobs by ConfigMaps with scripts, modules and default arguments to update them, when they change
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &awsv1alpha1.GlueJob{}, awsv1alpha1.ConfigMapIndex,
+		func(obj client.Object) []string {
+			glueJob, ok := obj.(*awsv1alpha1.GlueJob)
+			if !ok {
+				return nil
+			}
+			return configMapNames(glueJob)
+		})
+	if err != nil {
+		return err
+	}
+
+	// index GlueJobs by GlueScriptSources with scripts to upload scripts again, when new commit is fetched
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &awsv1alpha1.GlueJob{}, awsv1alpha1.ScriptSourceIndex,
+		func(obj client.Object) []string {
+			glueJob, ok := obj.(*awsv1alpha1.GlueJob)
+			if !ok {
+				return nil
+			}
+			source := glueJob.Spec.Command.ScriptSource
+			if source == nil || source.GitRef == nil {
+				return nil
+			}
+			return []string{source.GitRef.Name}
+		})
+	if err != nil {
+		return err
+	}
+
+	// index GlueJobs by Secrets with default arguments to update them, when they change
+	err = mgr.GetFieldIndexer().IndexField(context.Background(), &awsv1alpha1.GlueJob{}, awsv1alpha1.SecretIndex,
+		func(obj client.Object) []string {
+			glueJob, ok := obj.(*awsv1alpha1.GlueJob)
+			if !ok {
+				return nil
+			}
+			return secretNames(glueJob)
+		})
+	if err != nil {
+		return err
+	}
+
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.GlueJob{}, builder.WithPredicates(ignoreUpdateDeletePredicate(), shardPredicate(cfg))).
+		Watches(&awsv1alpha1.AWSProviderConfig{}, handler.EnqueueRequestsFromMapFunc(r.glueJobsForProviderConfig),
+			builder.WithPredicates(ignoreUpdateDeletePredicate())).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.glueJobsForConfigMap),
+			builder.OnlyMetadata).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.glueJobsForSecret), builder.OnlyMetadata).
+		Watches(&awsv1alpha1.GlueScriptSource{}, handler.EnqueueRequestsFromMapFunc(r.glueJobsForScriptSource),
+			builder.WithPredicates(scriptCommitChangedPredicate()))
+	if cfg.WatchNamespaceSelector != "" {
+		// GlueJobs are reconciled, when namespace labels start matching the selector
+		bldr = bldr.Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.glueJobsInNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}))
+	}
+	return bldr.
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: cfg.MaxConcurrentReconciles,
+			// per GlueJob exponential backoff and overall rate limit of reconciles
+			RateLimiter: workqueue.NewMaxOfRateLimiter(
+				workqueue.NewItemExponentialFailureRateLimiter(cfg.RateLimiterBaseDelay, cfg.RateLimiterMaxDelay),
+				&workqueue.BucketRateLimiter{
+					Limiter: rate.NewLimiter(rate.Limit(cfg.RateLimiterQPS), cfg.RateLimiterBurst),
+				},
+			),
+		}).
 		Complete(r)
 }
 
+// glueJobsInNamespace will return requests for GlueJobs in namespace
+func (r *GlueJobReconciler) glueJobsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := r.List(ctx, glueJobs, client.InNamespace(obj.GetName()))
+	if err != nil {
+		log.FromContext(ctx).V(0).Error(err, "Failed to list GlueJobs", "namespace", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(glueJobs.Items))
+	for i := range glueJobs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&glueJobs.Items[i])})
+	}
+	return requests
+}
+
+// glueJobsForProviderConfig will return requests for GlueJobs, which use AWSProviderConfig
+// either by reference or via namespace annotation
+func (r *GlueJobReconciler) glueJobsForProviderConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	reqLogger := log.FromContext(ctx).WithValues("awsproviderconfig", obj.GetName())
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := r.List(ctx, glueJobs, client.MatchingFields{awsv1alpha1.ProviderConfigIndex: obj.GetName()})
+	if err != nil {
+		reqLogger.V(0).Error(err, "Failed to list GlueJobs referencing AWSProviderConfig")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(glueJobs.Items))
+	for i := range glueJobs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&glueJobs.Items[i])})
+	}
+
+	namespaces, err := r.providerConfigNamespaces(ctx)
+	if err != nil {
+		reqLogger.V(0).Error(err, "Failed to list namespaces")
+		return requests
+	}
+	for _, ns := range namespaces {
+		if ns.Annotations[awsv1alpha1.ProviderConfigAnnotation] != obj.GetName() {
+			continue
+		}
+		nsGlueJobs := &awsv1alpha1.GlueJobList{}
+		err = r.List(ctx, nsGlueJobs, client.InNamespace(ns.Name))
+		if err != nil {
+			reqLogger.V(0).Error(err, "Failed to list GlueJobs", "namespace", ns.Name)
+			continue
+		}
+		for i := range nsGlueJobs.Items {
+			if nsGlueJobs.Items[i].Spec.ProviderConfigRef != nil {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&nsGlueJobs.Items[i])})
+		}
+	}
+	return requests
+}
+
+// providerConfigNamespaces will return the namespaces, which may select AWSProviderConfig by annotation.
+// Watched namespaces are read one by one bypassing the cache, because namespace-scoped installation
+// may only get them, otherwise all namespaces are listed
+func (r *GlueJobReconciler) providerConfigNamespaces(ctx context.Context) ([]corev1.Namespace, error) {
+	cfg := r.Config.Get()
+	if len(cfg.WatchNamespaces) == 0 {
+		namespaces := &corev1.NamespaceList{}
+		err := r.List(ctx, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		return namespaces.Items, nil
+	}
+	namespaces := make([]corev1.Namespace, 0, len(cfg.WatchNamespaces))
+	for _, name := range cfg.WatchNamespaces {
+		ns := corev1.Namespace{}
+		err := r.APIReader.Get(ctx, client.ObjectKey{Name: name}, &ns)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, nil
+}
+
 // ignoreUpdateDeletePredicater is brilliantly useful function, it will prevent multiple reconcile calls
 func ignoreUpdateDeletePredicate() predicate.Predicate {
 	return predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			// Ignore updates to CR status in which case metadata.Generation does not change
-			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+			// Ignore updates to CR status in which case metadata.Generation does not change,
+			// annotations (e.g. plan approval, pausing) and labels (e.g. shard selector) don't change
+			// generation, but must be reconciled
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				!maps.Equal(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()) ||
+				!maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
 			// Evaluates to false if the object has been confirmed deleted.
@@ -179,8 +463,11 @@ func (r *GlueJobReconciler) addOrRemoveFinalizer(glueJob *awsv1alpha1.GlueJob, a
 		// remove finalizer
 		controllerutil.RemoveFinalizer(glueJob, glueJobFinalizer)
 	}
-	// update resource
-	return r.Update(r.ctx, glueJob)
+	// update resource, keeping not yet persisted status
+	status := glueJob.Status.DeepCopy()
+	err := r.Update(r.ctx, glueJob)
+	glueJob.Status = *status
+	return err
 }
 
 // setLatestError will set latest error on condition
@@ -190,14 +477,12 @@ func (r *GlueJobReconciler) setLatestError(
 	errType string,
 ) (reconcile.Result, error) {
 	reterr := err
-	condition := metav1.Condition{
-		Type:               consts.StatusNotReady,
-		LastTransitionTime: metav1.NewTime(time.Now()),
-		Status:             metav1.ConditionTrue,
-		Reason:             errType,
-		Message:            fmt.Sprintf("%v", err),
-	}
-	gj.Status.Conditions = append(gj.Status.Conditions, condition)
+	setReadyCondition(&gj.Status.Conditions, metav1.Condition{
+		Type:    consts.StatusReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  errType,
+		Message: fmt.Sprintf("%v", err),
+	})
 	err = r.Status().Update(r.ctx, gj)
 	if err != nil {
 		reterr = kerrors.NewAggregate([]error{reterr, err})
@@ -205,6 +490,84 @@ func (r *GlueJobReconciler) setLatestError(
 	return ctrl.Result{}, reterr
 }
 
+// checkDuplicateName will return Conflict error if GlueJob, which was created earlier, uses the same Glue Job name
+func (r *GlueJobReconciler) checkDuplicateName(gj *awsv1alpha1.GlueJob) error {
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := r.List(r.ctx, glueJobs, client.MatchingFields{awsv1alpha1.GlueJobNameIndex: gj.Status.ResolvedName})
+	if err != nil {
+		return fmt.Errorf("failed to list GlueJobs with name %s: %w", gj.Status.ResolvedName, err)
+	}
+	for i := range glueJobs.Items {
+		other := &glueJobs.Items[i]
+		if other.UID == gj.UID || !other.CreatedBefore(gj) {
+			continue
+		}
+		return errors.NewConflict(awsv1alpha1.GroupVersion.WithResource("gluejobs").GroupResource(), gj.Name,
+			fmt.Errorf("name %s is already used by GlueJob %s/%s", gj.Status.ResolvedName, other.Namespace, other.Name))
+	}
+	return nil
+}
+
+// setConflictCondition will set Conflict condition, it's persisted with the next status update
+func (r *GlueJobReconciler) setConflictCondition(gj *awsv1alpha1.GlueJob, duplicateErr error) {
+	condition := metav1.Condition{
+		Type:    consts.StatusConflict,
+		Status:  metav1.ConditionFalse,
+		Reason:  consts.ReasonUniqueName,
+		Message: "Glue Job name is not used by other GlueJobs",
+	}
+	if duplicateErr != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = consts.ReasonDuplicateName
+		condition.Message = duplicateErr.Error()
+	}
+	meta.SetStatusCondition(&gj.Status.Conditions, condition)
+}
+
+// setPausedCondition will set Paused condition and return true if GlueJob is paused by annotation,
+// pausing and resuming are recorded as events. Condition is persisted with the next status update
+func (r *GlueJobReconciler) setPausedCondition(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob) bool {
+	paused := gj.Annotations[awsv1alpha1.PausedAnnotation] == "true"
+	wasPaused := meta.IsStatusConditionTrue(gj.Status.Conditions, consts.StatusPaused)
+	condition := metav1.Condition{
+		Type:    consts.StatusPaused,
+		Status:  metav1.ConditionFalse,
+		Reason:  consts.ReasonResumed,
+		Message: "Reconciliation is not paused",
+	}
+	if paused {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = consts.ReasonPaused
+		condition.Message = fmt.Sprintf("Reconciliation is paused by annotation %s", awsv1alpha1.PausedAnnotation)
+	}
+	meta.SetStatusCondition(&gj.Status.Conditions, condition)
+	switch {
+	case paused && !wasPaused:
+		reqLogger.V(0).Info("GlueJob reconciliation is paused")
+		r.Recorder.Event(gj, corev1.EventTypeNormal, consts.ReasonPaused, condition.Message)
+	case !paused && wasPaused:
+		reqLogger.V(0).Info("GlueJob reconciliation is resumed")
+		r.Recorder.Event(gj, corev1.EventTypeNormal, consts.ReasonResumed, "Reconciliation is resumed")
+	}
+	return paused
+}
+
+// setOwnershipCondition will set OwnershipConflict condition, it's persisted with the next status update
+func (r *GlueJobReconciler) setOwnershipCondition(conditions *[]metav1.Condition, conflictErr error) {
+	condition := metav1.Condition{
+		Type:    consts.StatusOwnershipConflict,
+		Status:  metav1.ConditionFalse,
+		Reason:  consts.ReasonOwned,
+		Message: "Glue Job is owned by this GlueJob",
+	}
+	if conflictErr != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = consts.ReasonOwnedByOther
+		condition.Message = conflictErr.Error()
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
 func (r *GlueJobReconciler) createJob(awsGJ *glue.Job, reqLogger logr.Logger) error {
 	reqLogger.V(0).Info("Create GlueJob")
 	err := awsGJ.CreateJob()
@@ -226,19 +589,74 @@ func (r *GlueJobReconciler) updateJob(awsGJ *glue.Job, reqLogger logr.Logger) er
 // Function would always return reconcile with requeue and time to requeue
 func (r *GlueJobReconciler) succReconcileRet(gj *awsv1alpha1.GlueJob,
 	reqLogger logr.Logger, message string) (reconcile.Result, error) {
-	condition := metav1.Condition{
-		Type:               consts.StatusReady,
-		LastTransitionTime: metav1.NewTime(time.Now()),
-		Status:             metav1.ConditionTrue,
-		Reason:             consts.SuccessReconcile,
-		Message:            message,
+	setReadyCondition(&gj.Status.Conditions, metav1.Condition{
+		Type:    consts.StatusReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  consts.SuccessReconcile,
+		Message: message,
+	})
+	err := r.Status().Update(r.ctx, gj)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-	gj.Status.Conditions = append(gj.Status.Conditions, condition)
+	// GlueJob is looked at again after resync period to recover from drift and out-of-band deletions
+	return ctrl.Result{RequeueAfter: r.resyncPeriod(reqLogger, gj)}, nil
+}
+
+// missingReconcileRet will mark GlueJob not ready, because Glue Jobs of placements were deleted
+// outside of the operator and are not recreated. It's checked again after resync period
+func (r *GlueJobReconciler) missingReconcileRet(gj *awsv1alpha1.GlueJob,
+	reqLogger logr.Logger, placements []string) (reconcile.Result, error) {
+	message := "Glue Job was deleted outside of the operator"
+	if len(placements) > 1 || placements[0] != defaultPlacement {
+		message = fmt.Sprintf("%s in placements %s", message, strings.Join(placements, ", "))
+	}
+	setReadyCondition(&gj.Status.Conditions, metav1.Condition{
+		Type:    consts.StatusReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  consts.ReasonMissing,
+		Message: message,
+	})
 	err := r.Status().Update(r.ctx, gj)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.resyncPeriod(reqLogger, gj)}, nil
+}
+
+// resyncPeriod will return resync period of GlueJob with jitter applied, 0 disables resync.
+// Resync period annotation of GlueJob overrides the operator resync period
+func (r *GlueJobReconciler) resyncPeriod(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob) time.Duration {
+	cfg := r.Config.Get()
+	period := cfg.ResyncPeriod
+	if value, ok := gj.Annotations[awsv1alpha1.ResyncPeriodAnnotation]; ok {
+		override, err := time.ParseDuration(value)
+		if err != nil || override < 0 {
+			reqLogger.V(0).Info("Ignoring invalid resync period annotation", "value", value)
+		} else {
+			period = override
+		}
+	}
+	if period <= 0 {
+		return 0
+	}
+	if cfg.ResyncJitter <= 0 {
+		// wait.Jitter falls back to the factor 1.0
+		return period
+	}
+	return wait.Jitter(period, cfg.ResyncJitter)
+}
+
+// setReadyCondition will set Ready condition. Older versions of the operator appended
+// Ready and NotReady conditions on every reconcile, they are cleaned up
+func setReadyCondition(conditions *[]metav1.Condition, condition metav1.Condition) {
+	meta.RemoveStatusCondition(conditions, consts.StatusNotReady)
+	if existing := meta.FindStatusCondition(*conditions, consts.StatusReady); existing != nil {
+		last := *existing
+		meta.RemoveStatusCondition(conditions, consts.StatusReady)
+		*conditions = append(*conditions, last)
+	}
+	meta.SetStatusCondition(conditions, condition)
 }
 
 // finalizeGlueJob is part of finalizers logic and deletes the GlueJob on AWS
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+)
+
+func TestResyncPeriod(t *testing.T) {
+	tests := []struct {
+		name       string
+		period     time.Duration
+		jitter     float64
+		annotation string
+		min        time.Duration
+		max        time.Duration
+	}{
+		{name: "operator period", period: 10 * time.Minute, min: 10 * time.Minute, max: 10 * time.Minute},
+		{name: "jitter", period: 10 * time.Minute, jitter: 0.1, min: 10 * time.Minute, max: 11 * time.Minute},
+		{name: "disabled", jitter: 0.1},
+		{name: "annotation override", period: 10 * time.Minute, annotation: "30m", min: 30 * time.Minute, max: 30 * time.Minute},
+		{name: "annotation disables", period: 10 * time.Minute, jitter: 0.1, annotation: "0"},
+		{name: "invalid annotation", period: 10 * time.Minute, annotation: "-1m", min: 10 * time.Minute, max: 10 * time.Minute},
+		{name: "unparsable annotation", period: 10 * time.Minute, annotation: "often", min: 10 * time.Minute, max: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GlueJobReconciler{Config: config.StaticStore(config.OperatorConfig{
+				ResyncPeriod: tt.period,
+				ResyncJitter: tt.jitter,
+			})}
+			gj := &awsv1alpha1.GlueJob{}
+			if tt.annotation != "" {
+				gj.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{
+					awsv1alpha1.ResyncPeriodAnnotation: tt.annotation,
+				}}
+			}
+			for i := 0; i < 20; i++ {
+				period := r.resyncPeriod(logr.Discard(), gj)
+				if period < tt.min || period > tt.max {
+					t.Fatalf("expected period from %v to %v, got %v", tt.min, tt.max, period)
+				}
+			}
+		})
+	}
+}
+
+func TestGlueJobsForProviderConfigNamespaced(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = awsv1alpha1.AddToScheme(scheme)
+	annotated := map[string]string{awsv1alpha1.ProviderConfigAnnotation: "team-a"}
+	objects := []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-dev", Annotations: annotated}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a-prod"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Annotations: annotated}},
+		&awsv1alpha1.GlueJob{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a-dev", Name: "by-annotation"}},
+		&awsv1alpha1.GlueJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a-prod", Name: "by-ref"},
+			Spec: awsv1alpha1.GlueJobSpec{
+				ProviderConfigRef: &awsv1alpha1.ProviderConfigReference{Name: "team-a"},
+			},
+		},
+		&awsv1alpha1.GlueJob{ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "unwatched"}},
+	}
+	// namespace-scoped installation may only get the watched namespaces
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", nil)
+	cached := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithIndex(&awsv1alpha1.GlueJob{}, awsv1alpha1.ProviderConfigIndex, func(obj client.Object) []string {
+			if ref := obj.(*awsv1alpha1.GlueJob).Spec.ProviderConfigRef; ref != nil {
+				return []string{ref.Name}
+			}
+			return nil
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*corev1.NamespaceList); ok {
+					return forbidden
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+	r := &GlueJobReconciler{
+		Client:    cached,
+		APIReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Config: config.StaticStore(config.OperatorConfig{
+			WatchNamespaces: []string{"team-a-dev", "team-a-prod", "team-a-gone"},
+		}),
+	}
+
+	requests := r.glueJobsForProviderConfig(context.Background(),
+		&awsv1alpha1.AWSProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+	names := make(map[string]bool, len(requests))
+	for _, request := range requests {
+		names[request.Namespace+"/"+request.Name] = true
+	}
+	if len(names) != 2 || !names["team-a-dev/by-annotation"] || !names["team-a-prod/by-ref"] {
+		t.Errorf("expected GlueJobs of watched namespaces, got %v", requests)
+	}
+}
+
+func TestSetPausedCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		paused    bool
+		wasPaused bool
+		status    metav1.ConditionStatus
+		event     string
+	}{
+		{name: "not paused", status: metav1.ConditionFalse},
+		{name: "pausing", paused: true, status: metav1.ConditionTrue, event: consts.ReasonPaused},
+		{name: "still paused", paused: true, wasPaused: true, status: metav1.ConditionTrue},
+		{name: "resuming", wasPaused: true, status: metav1.ConditionFalse, event: consts.ReasonResumed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &GlueJobReconciler{Recorder: recorder}
+			gj := &awsv1alpha1.GlueJob{}
+			if tt.paused {
+				gj.Annotations = map[string]string{awsv1alpha1.PausedAnnotation: "true"}
+			}
+			if tt.wasPaused {
+				meta.SetStatusCondition(&gj.Status.Conditions, metav1.Condition{
+					Type: consts.StatusPaused, Status: metav1.ConditionTrue, Reason: consts.ReasonPaused,
+				})
+			}
+			if paused := r.setPausedCondition(logr.Discard(), gj); paused != tt.paused {
+				t.Errorf("expected paused %v, got %v", tt.paused, paused)
+			}
+			condition := meta.FindStatusCondition(gj.Status.Conditions, consts.StatusPaused)
+			if condition == nil || condition.Status != tt.status {
+				t.Errorf("expected %s Paused condition, got %v", tt.status, condition)
+			}
+			event := ""
+			if len(recorder.Events) > 0 {
+				event = <-recorder.Events
+			}
+			if !strings.Contains(event, tt.event) || (tt.event == "") != (event == "") {
+				t.Errorf("expected event %q, got %q", tt.event, event)
+			}
+		})
+	}
+}
+
+func TestReconcilePaused(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = awsv1alpha1.AddToScheme(scheme)
+	gj := &awsv1alpha1.GlueJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "team-a",
+			Name:        "etl",
+			Annotations: map[string]string{awsv1alpha1.PausedAnnotation: "true"},
+		},
+		// the name without the namespace prefix fails the reconcile before any AWS call
+		Spec: awsv1alpha1.GlueJobSpec{Name: "etl"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gj).
+		WithStatusSubresource(&awsv1alpha1.GlueJob{}).Build()
+	// AWS clients aren't set, so any AWS call of paused GlueJob would panic
+	r := &GlueJobReconciler{
+		Client:   fakeClient,
+		Config:   config.StaticStore(config.OperatorConfig{NamespacePrefixes: map[string]string{"team-a": "team-a-"}}),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.Background()
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "etl"}}
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, request.NamespacedName, gj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(gj.Status.Conditions, consts.StatusPaused) {
+		t.Errorf("expected Paused condition, got %v", gj.Status.Conditions)
+	}
+	if meta.FindStatusCondition(gj.Status.Conditions, consts.StatusReady) != nil {
+		t.Errorf("expected paused GlueJob not to be reconciled, got %v", gj.Status.Conditions)
+	}
+
+	delete(gj.Annotations, awsv1alpha1.PausedAnnotation)
+	if err := fakeClient.Update(ctx, gj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, request); err == nil {
+		t.Error("expected error of invalid name of resumed GlueJob")
+	}
+	if err := fakeClient.Get(ctx, request.NamespacedName, gj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.IsStatusConditionTrue(gj.Status.Conditions, consts.StatusPaused) {
+		t.Errorf("expected Paused condition to be false, got %v", gj.Status.Conditions)
+	}
+	ready := meta.FindStatusCondition(gj.Status.Conditions, consts.StatusReady)
+	if ready == nil || ready.Reason != "InvalidGlueJobName" {
+		t.Errorf("expected resumed GlueJob to be reconciled, got %v", ready)
+	}
+}
+
+func TestIgnoreUpdateDeletePredicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      metav1.ObjectMeta
+		new      metav1.ObjectMeta
+		expected bool
+	}{
+		{name: "status update", old: metav1.ObjectMeta{Generation: 1}, new: metav1.ObjectMeta{Generation: 1}},
+		{name: "spec update", old: metav1.ObjectMeta{Generation: 1}, new: metav1.ObjectMeta{Generation: 2}, expected: true},
+		{
+			name:     "pausing",
+			old:      metav1.ObjectMeta{Generation: 1},
+			new:      metav1.ObjectMeta{Generation: 1, Annotations: map[string]string{awsv1alpha1.PausedAnnotation: "true"}},
+			expected: true,
+		},
+		{
+			name:     "resuming",
+			old:      metav1.ObjectMeta{Generation: 1, Annotations: map[string]string{awsv1alpha1.PausedAnnotation: "true"}},
+			new:      metav1.ObjectMeta{Generation: 1},
+			expected: true,
+		},
+		{
+			name:     "label update",
+			old:      metav1.ObjectMeta{Generation: 1},
+			new:      metav1.ObjectMeta{Generation: 1, Labels: map[string]string{"shard": "a"}},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event.UpdateEvent{
+				ObjectOld: &awsv1alpha1.GlueJob{ObjectMeta: tt.old},
+				ObjectNew: &awsv1alpha1.GlueJob{ObjectMeta: tt.new},
+			}
+			if got := ignoreUpdateDeletePredicate().Update(e); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+// setModulesLocation will bundle Python modules of GlueJob and add the content addressed S3 path of the bundle
+// to --extra-py-files. It returns the bundle, its S3 path, where it must be uploaded, and the hash of its sources.
+// The bundle is nil, if the placement status records the bundle of the same sources
+func (r *GlueJobReconciler) setModulesLocation(gj *awsv1alpha1.GlueJob, awsClients *awsclient.Clients,
+	status *awsv1alpha1.GlueJobPlacementStatus) ([]byte, string, string, error) {
+	modules := gj.Spec.PythonModules
+	// the bundle isn't needed to delete Glue Job, its ConfigMaps may be deleted already
+	if modules == nil || !gj.DeletionTimestamp.IsZero() {
+		return nil, "", "", nil
+	}
+	cfg := r.Config.Get()
+	bucket := scriptBucket(cfg, awsClients)
+	if bucket == "" {
+		return nil, "", "", fmt.Errorf("script bucket isn't configured for Python modules")
+	}
+	files := glue.ModuleFiles{}
+	for _, ref := range modules.ConfigMaps {
+		err := r.addConfigMapModules(files, gj.Namespace, ref)
+		if err != nil {
+			return nil, "", "", err
+		}
+	}
+	var staged *glue.StagedModules
+	if modules.S3Prefix != "" {
+		var err error
+		staged, err = glue.ListStaged(r.ctx, awsClients.S3, modules.S3Prefix)
+		if err != nil {
+			return nil, "", "", err
+		}
+		// objects are read with the operator credentials, so only buckets meant for it can be read
+		if !sourceBucketAllowed(cfg, awsClients, staged.Bucket) {
+			return nil, "", "", fmt.Errorf("bucket %s of s3Prefix isn't the script bucket or one of source buckets",
+				staged.Bucket)
+		}
+	}
+	hash := files.Hash(staged)
+	if status.ModulesObject != "" && status.ModulesHash == hash {
+		gj.Spec.DefaultArguments = glue.WithModules(gj.Spec.DefaultArguments, status.ModulesObject)
+		return nil, status.ModulesObject, hash, nil
+	}
+	if staged != nil {
+		err := files.AddStaged(r.ctx, awsClients.S3, staged)
+		if err != nil {
+			return nil, "", "", err
+		}
+	}
+	bundle, err := files.Bundle()
+	if err != nil {
+		return nil, "", "", err
+	}
+	location := glue.ScriptObject(bucket, cfg.ScriptPrefix, gj.Status.ResolvedName, glue.ModulesFileName, bundle)
+	gj.Spec.DefaultArguments = glue.WithModules(gj.Spec.DefaultArguments, location)
+	return bundle, location, hash, nil
+}
+
+// addConfigMapModules will add keys of ConfigMap to the bundle of Python modules
+func (r *GlueJobReconciler) addConfigMapModules(files glue.ModuleFiles, namespace string,
+	ref awsv1alpha1.GlueJobModulesConfigMap) error {
+	configMap := &corev1.ConfigMap{}
+	err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap)
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s with Python modules: %w", ref.Name, err)
+	}
+	for key, content := range configMap.Data {
+		err = files.Add(ref.Path, key, []byte(content))
+		if err != nil {
+			return fmt.Errorf("ConfigMap %s: %w", ref.Name, err)
+		}
+	}
+	for key, content := range configMap.BinaryData {
+		err = files.Add(ref.Path, key, content)
+		if err != nil {
+			return fmt.Errorf("ConfigMap %s: %w", ref.Name, err)
+		}
+	}
+	return nil
+}
+
+// configMapNames will return the names of ConfigMaps with the script, Python modules and default arguments of GlueJob
+func configMapNames(gj *awsv1alpha1.GlueJob) []string {
+	names := make(map[string]struct{})
+	if source := gj.Spec.Command.ScriptSource; source != nil && source.ConfigMapKeyRef != nil {
+		names[source.ConfigMapKeyRef.Name] = struct{}{}
+	}
+	if gj.Spec.PythonModules != nil {
+		for _, ref := range gj.Spec.PythonModules.ConfigMaps {
+			names[ref.Name] = struct{}{}
+		}
+	}
+	for _, source := range gj.Spec.DefaultArgumentsFrom {
+		if source.ConfigMapRef != nil {
+			names[source.ConfigMapRef.Name] = struct{}{}
+		}
+	}
+	for _, valueFrom := range gj.Spec.DefaultArgumentsValueFrom {
+		if valueFrom.ConfigMapKeyRef != nil {
+			names[valueFrom.ConfigMapKeyRef.Name] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+func TestSetModulesLocation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "modules"},
+		Data:       map[string]string{"utils.py": "X = 1"},
+	}
+	r := &GlueJobReconciler{
+		ctx:       context.Background(),
+		APIReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		Config:    config.NewStore(config.OperatorConfig{ScriptBucket: "scripts", ScriptPrefix: "glue"}, "", logr.Discard()),
+	}
+	newGlueJob := func() *awsv1alpha1.GlueJob {
+		gj := &awsv1alpha1.GlueJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "etl"},
+			Status:     awsv1alpha1.GlueJobStatus{ResolvedName: "prod_team-a_etl"},
+		}
+		gj.Spec.PythonModules = &awsv1alpha1.GlueJobPythonModules{
+			ConfigMaps: []awsv1alpha1.GlueJobModulesConfigMap{{Name: "modules", Path: "mypkg"}},
+		}
+		return gj
+	}
+	status := &awsv1alpha1.GlueJobPlacementStatus{}
+	awsClients := &awsclient.Clients{}
+
+	gj := newGlueJob()
+	bundle, location, hash, err := r.setModulesLocation(gj, awsClients, status)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bundle == nil || location == "" || hash == "" {
+		t.Fatalf("expected bundle, location and hash, got %d bytes, %s, %s", len(bundle), location, hash)
+	}
+	if gj.Spec.DefaultArguments["--extra-py-files"] != location {
+		t.Errorf("expected --extra-py-files %s, got %s", location, gj.Spec.DefaultArguments["--extra-py-files"])
+	}
+
+	// the bundle of the same sources is uploaded already
+	status.ModulesObject, status.ModulesHash = location, hash
+	gj = newGlueJob()
+	bundle, reused, _, err := r.setModulesLocation(gj, awsClients, status)
+	if err != nil || bundle != nil || reused != location {
+		t.Errorf("expected uploaded bundle %s to be reused, got %d bytes at %s: %v", location, len(bundle), reused, err)
+	}
+	if gj.Spec.DefaultArguments["--extra-py-files"] != location {
+		t.Errorf("expected --extra-py-files %s, got %s", location, gj.Spec.DefaultArguments["--extra-py-files"])
+	}
+
+	status.ModulesHash = "stale"
+	bundle, _, _, err = r.setModulesLocation(newGlueJob(), awsClients, status)
+	if err != nil || bundle == nil {
+		t.Errorf("expected modules to be bundled again, got %d bytes: %v", len(bundle), err)
+	}
+}
+
+func TestSourceBucketAllowed(t *testing.T) {
+	cfg := config.OperatorConfig{ScriptBucket: "scripts", SourceBuckets: []string{"staging"}}
+	tests := []struct {
+		bucket       string
+		scriptBucket string
+		expected     bool
+	}{
+		{bucket: "scripts", expected: true},
+		{bucket: "staging", expected: true},
+		{bucket: "secrets"},
+		{bucket: "placement", scriptBucket: "placement", expected: true},
+		{bucket: "scripts", scriptBucket: "placement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bucket+"/"+tt.scriptBucket, func(t *testing.T) {
+			allowed := sourceBucketAllowed(cfg, &awsclient.Clients{ScriptBucket: tt.scriptBucket}, tt.bucket)
+			if allowed != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, allowed)
+			}
+		})
+	}
+}
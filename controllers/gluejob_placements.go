@@ -0,0 +1,566 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+// defaultPlacement is the name of the only placement of GlueJob without placements
+const defaultPlacement = "default"
+
+// placementsResult is the outcome of reconciling Glue Job in all placements
+type placementsResult struct {
+	// applied is true if Glue Job was created or updated in any placement
+	applied bool
+	// conflict is the first ownership conflict
+	conflict error
+	// err aggregates errors of all placements, reason is the reason of the first one
+	err    error
+	reason string
+	// message describes successful reconcile
+	message string
+	// missing are the placements, which Glue Jobs were deleted outside of the operator and are not recreated
+	missing []string
+}
+
+// managementPolicy will return management policy of GlueJob, the operator wide observe only mode overrides it
+func (r *GlueJobReconciler) managementPolicy(gj *awsv1alpha1.GlueJob) string {
+	if r.Config.Get().ObserveOnly {
+		return awsv1alpha1.ManagementPolicyObserveOnly
+	}
+	if gj.Spec.ManagementPolicy == "" {
+		return awsv1alpha1.ManagementPolicyFull
+	}
+	return gj.Spec.ManagementPolicy
+}
+
+// placements will return placements of GlueJob, GlueJob without placements
+// has single placement using AWSProviderConfig of GlueJob
+func placements(gj *awsv1alpha1.GlueJob) []awsv1alpha1.GlueJobPlacement {
+	if len(gj.Spec.Placements) == 0 {
+		return []awsv1alpha1.GlueJobPlacement{{Name: defaultPlacement}}
+	}
+	return gj.Spec.Placements
+}
+
+// placementGlueJob is Glue Job of GlueJob in single placement. Glue Jobs of placements are built
+// once per reconcile and shared by planning of approvals and reconciling
+type placementGlueJob struct {
+	placement *awsv1alpha1.GlueJobPlacement
+	// status is the status of placement, which gets account and region of placement
+	status awsv1alpha1.GlueJobPlacementStatus
+	job    *glue.Job
+	err    error
+	// removed is true, if placement was removed from the spec
+	removed bool
+}
+
+// placementGlueJobs will return Glue Jobs of active placements of GlueJob followed by
+// Glue Jobs of placements removed from the spec
+func (r *GlueJobReconciler) placementGlueJobs(gj *awsv1alpha1.GlueJob) []placementGlueJob {
+	active := placements(gj)
+	jobs := make([]placementGlueJob, 0, len(active)+len(gj.Status.Placements))
+	names := make(map[string]struct{}, len(active))
+	for i := range active {
+		names[active[i].Name] = struct{}{}
+		pj := placementGlueJob{placement: &active[i], status: placementStatus(gj, &active[i])}
+		pj.job, pj.err = r.placementJob(gj, pj.placement, &pj.status)
+		jobs = append(jobs, pj)
+	}
+	for i := range gj.Status.Placements {
+		if _, ok := names[gj.Status.Placements[i].Name]; ok {
+			continue
+		}
+		pj := placementGlueJob{status: *gj.Status.Placements[i].DeepCopy(), removed: true}
+		pj.placement = &pj.status.Placement
+		pj.job, pj.err = r.placementJob(gj, pj.placement, &pj.status)
+		jobs = append(jobs, pj)
+	}
+	return jobs
+}
+
+// reconcilePlacements will create, update or (if GlueJob is deleted) delete Glue Job in every placement
+// and delete Glue Jobs of placements removed from the spec. Placement statuses are set on GlueJob
+// Mutations not allowed by policy are only planned, held describes why
+func (r *GlueJobReconciler) reconcilePlacements(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob,
+	jobs []placementGlueJob, policy, held string, deleting bool) placementsResult {
+	result := placementsResult{message: "Successfully updated GlueJob"}
+	errs := make([]error, 0)
+	fail := func(err error, reason string) {
+		if len(errs) == 0 {
+			result.reason = reason
+		}
+		errs = append(errs, err)
+	}
+
+	statuses := make([]awsv1alpha1.GlueJobPlacementStatus, 0, len(jobs))
+	// accounts and regions of active placements
+	locations := make(map[string]struct{}, len(jobs))
+	// objects uploaded by the operator, which active placements use
+	inUse := make(map[string]struct{}, 2*len(jobs))
+	active := 0
+	for i := range jobs {
+		pj := &jobs[i]
+		if pj.removed {
+			continue
+		}
+		active++
+		placement, status := pj.placement, &pj.status
+		outcome := r.reconcilePlacement(reqLogger.WithValues("placement", placement.Name),
+			gj, pj, policy, held, deleting)
+		r.recordPlannedChanges(gj, placement.Name, outcome.planned)
+		if status.AccountID != "" {
+			locations[status.AccountID+"/"+status.Region] = struct{}{}
+		}
+		for _, object := range []string{status.ScriptObject, status.ModulesObject} {
+			if object != "" {
+				inUse[object] = struct{}{}
+			}
+		}
+		if outcome.conflict != nil && result.conflict == nil {
+			result.conflict = outcome.conflict
+		}
+		if outcome.missing {
+			result.missing = append(result.missing, placement.Name)
+		}
+		if outcome.err != nil {
+			fail(outcome.err, outcome.reason)
+		} else if !deleting && policy != awsv1alpha1.ManagementPolicyObserveOnly {
+			result.applied = true
+			if outcome.created {
+				result.message = "Successfully created GlueJob"
+			}
+		}
+		statuses = append(statuses, *status)
+	}
+
+	// Glue Jobs of removed placements are deleted, unless an active placement uses the same account and region,
+	// and so are their uploaded objects, unless an active placement uses the same object
+	for i := range jobs {
+		pj := &jobs[i]
+		if !pj.removed {
+			continue
+		}
+		keep, err := r.removePlacement(reqLogger.WithValues("placement", pj.status.Name), gj, pj, policy, held,
+			locations, inUse)
+		if err != nil {
+			fail(err, "DeleteGlueJobFailed")
+		}
+		if keep || err != nil {
+			statuses = append(statuses, pj.status)
+		}
+	}
+
+	gj.Status.Placements = statuses
+	// AWS clients of placements gone from the status aren't needed anymore
+	kept := make([]string, 0, len(statuses))
+	for i := range statuses {
+		kept = append(kept, statuses[i].Name)
+	}
+	r.AWS.ForgetPlacements(gj.Namespace, gj.Name, kept...)
+	setSyncedCondition(&gj.Status.Conditions, plannedChanges(statuses), held)
+	if !deleting {
+		setScriptAvailableCondition(&gj.Status.Conditions, missingArtifacts(statuses), artifactsCheckErr(statuses))
+	}
+	if active > 1 {
+		result.message = fmt.Sprintf("%s in %d placements", result.message, active)
+	}
+	result.err = kerrors.NewAggregate(errs)
+	return result
+}
+
+// placementStatus will return the last status of placement or a new one
+func placementStatus(gj *awsv1alpha1.GlueJob, placement *awsv1alpha1.GlueJobPlacement) awsv1alpha1.GlueJobPlacementStatus {
+	status := awsv1alpha1.GlueJobPlacementStatus{Name: placement.Name}
+	for i := range gj.Status.Placements {
+		if gj.Status.Placements[i].Name == placement.Name {
+			status = *gj.Status.Placements[i].DeepCopy()
+		}
+	}
+	status.Placement = *placement.DeepCopy()
+	return status
+}
+
+// placementOutcome is the outcome of reconciling Glue Job in single placement
+type placementOutcome struct {
+	created  bool
+	conflict error
+	err      error
+	reason   string
+	// planned are the mutations, which were not made because of the management policy
+	planned []string
+	// missing is true if Glue Job was deleted outside of the operator and is not recreated
+	missing bool
+}
+
+// reconcilePlacement will create, update or delete Glue Job in placement
+func (r *GlueJobReconciler) reconcilePlacement(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob,
+	pj *placementGlueJob, policy, held string, deleting bool) placementOutcome {
+	placement, status := pj.placement, &pj.status
+	failed := func(outcome placementOutcome, err error, reason string) placementOutcome {
+		if placement.Name != defaultPlacement {
+			err = fmt.Errorf("placement %s: %w", placement.Name, err)
+		}
+		setPlacementReady(status, err, reason, "")
+		outcome.err = err
+		outcome.reason = reason
+		return outcome
+	}
+
+	awsGlueJob, err := pj.job, pj.err
+	if err != nil {
+		return failed(placementOutcome{}, err, "CreateNewGlueJobFailed")
+	}
+	// Never mutate Glue Job, which is owned by another cluster or GlueJob
+	outcome := placementOutcome{conflict: awsGlueJob.OwnershipConflict()}
+	r.setOwnershipCondition(&status.Conditions, outcome.conflict)
+
+	if deleting {
+		// Glue Job owned by someone else is left on AWS.
+		if outcome.conflict != nil {
+			reqLogger.V(0).Info("Not deleting GlueJob on AWS owned by someone else", "reason", outcome.conflict.Error())
+			return outcome
+		}
+		if policy != awsv1alpha1.ManagementPolicyFull {
+			reqLogger.V(0).Info("Not deleting GlueJob on AWS due to management policy", "managementPolicy", policy)
+			outcome.planned = awsGlueJob.DeletePlan()
+			return outcome
+		}
+		err = r.finalizeGlueJob(reqLogger, gj, awsGlueJob)
+		if err != nil {
+			return failed(outcome, err, "DeleteGlueJobFailed")
+		}
+		r.deleteUploaded(reqLogger, awsGlueJob, status, nil)
+		return outcome
+	}
+
+	if outcome.conflict != nil {
+		return failed(outcome, outcome.conflict, consts.StatusOwnershipConflict)
+	}
+
+	// Glue Job, which existed before, was deleted outside of the operator
+	created := !awsGlueJob.JobExists()
+	if created && status.Exists {
+		// Glue Job marked missing stays missing until it's back, so it's reported only once
+		if ready := meta.FindStatusCondition(status.Conditions, consts.StatusReady); ready == nil ||
+			ready.Reason != consts.ReasonMissing {
+			reqLogger.V(0).Info("Glue Job was deleted outside of the operator")
+			r.Recorder.Eventf(gj, corev1.EventTypeWarning, consts.ReasonMissing,
+				"Glue Job %s%s was deleted outside of the operator", gj.Status.ResolvedName, placementSuffix(placement.Name))
+		}
+		if gj.Spec.MissingJobPolicy == awsv1alpha1.MissingJobPolicyMarkMissing {
+			outcome.missing = true
+			setPlacementReady(status, fmt.Errorf("Glue Job is missing on AWS"), consts.ReasonMissing, "")
+			return outcome
+		}
+	}
+
+	// script and modules are uploaded, before artifacts are checked, unless Glue Job is only observed
+	mutate := policy != awsv1alpha1.ManagementPolicyObserveOnly &&
+		(policy != awsv1alpha1.ManagementPolicyCreateOnly || created)
+	if mutate {
+		uploaded, err := awsGlueJob.UploadScript()
+		if err != nil {
+			return failed(outcome, err, "UploadScriptFailed")
+		}
+		if uploaded {
+			reqLogger.V(0).Info("Uploaded script", "script", awsGlueJob.ScriptLocation())
+			r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonScriptUploaded,
+				"Uploaded script to %s%s", awsGlueJob.ScriptLocation(), placementSuffix(placement.Name))
+		}
+		uploaded, err = awsGlueJob.UploadModules()
+		if err != nil {
+			return failed(outcome, err, "UploadModulesFailed")
+		}
+		if uploaded {
+			reqLogger.V(0).Info("Uploaded Python modules", "modules", awsGlueJob.UploadedModules())
+			r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonModulesUploaded,
+				"Uploaded Python modules to %s%s", awsGlueJob.UploadedModules(), placementSuffix(placement.Name))
+		}
+	}
+
+	// script and dependencies must exist in S3, otherwise Glue Job would fail at run time
+	missingArtifacts, artifactsErr := awsGlueJob.MissingArtifacts()
+	if artifactsErr != nil {
+		reqLogger.V(0).Info("Failed to check script and dependencies in S3", "reason", artifactsErr.Error())
+	}
+	status.MissingArtifacts = missingArtifacts
+	if slices.Contains(missingArtifacts, status.ModulesObject) {
+		// the recorded bundle was deleted, so it's bundled and uploaded again
+		status.ModulesHash = ""
+	}
+	if slices.Contains(missingArtifacts, status.ScriptObject) {
+		// the recorded copy of pinned script was deleted, so it's copied again
+		status.PinnedScript = ""
+	}
+	setScriptAvailableCondition(&status.Conditions, missingArtifacts, artifactsErr)
+	scriptChanged := len(missingArtifacts) == 0 &&
+		r.checkScriptVersion(reqLogger, gj, awsGlueJob, placement.Name, status)
+
+	// planned changes are only reported, if management policy doesn't allow them
+	if !mutate {
+		outcome.planned = awsGlueJob.Plan()
+		status.PlannedChanges = outcome.planned
+		status.Exists = status.Exists || !created
+		setPlacementReady(status, nil, consts.SuccessReconcile, "Observed Glue Job")
+		setSyncedCondition(&status.Conditions, len(outcome.planned), held)
+		return outcome
+	}
+
+	if len(missingArtifacts) > 0 {
+		return failed(outcome, fmt.Errorf("missing in S3: %s", strings.Join(missingArtifacts, ", ")),
+			consts.ReasonArtifactsMissing)
+	}
+	// secrets must exist and be readable by the role, otherwise Glue Job would fail at run time
+	err = awsGlueJob.ValidateSecrets()
+	if err != nil {
+		return failed(outcome, err, consts.ReasonSecretsInvalid)
+	}
+
+	outcome.created = created
+	message := "Successfully updated Glue Job"
+	if outcome.created {
+		// 3. if not exists, create job on AWS
+		err = r.createJob(awsGlueJob, reqLogger)
+		message = "Successfully created Glue Job"
+	} else {
+		// 2. if exists, update job up to date
+		err = r.updateJob(awsGlueJob, reqLogger)
+	}
+	if err != nil {
+		return failed(outcome, err, "GlueJobFailed")
+	}
+	if outcome.created && status.Exists {
+		status.Recreations++
+		message = "Successfully recreated Glue Job"
+	}
+	status.Exists = true
+	status.PlannedChanges = nil
+	r.deleteSuperseded(reqLogger, awsGlueJob, &status.ScriptObject, awsGlueJob.UploadedScript())
+	status.PinnedScript = awsGlueJob.PinnedScript()
+	status.ScriptCommit = awsGlueJob.ScriptCommit()
+	r.deleteSuperseded(reqLogger, awsGlueJob, &status.ModulesObject, awsGlueJob.UploadedModules())
+	status.ModulesHash = awsGlueJob.ModulesHash()
+	if scriptChanged && gj.Spec.SmokeTest != nil {
+		r.startSmokeTest(reqLogger, gj, awsGlueJob, placement.Name, status)
+	}
+	// source control actions need Glue Job on AWS, so they follow create and update
+	err = r.syncSourceControl(reqLogger, gj, awsGlueJob, placement.Name, status)
+	if err != nil {
+		return failed(outcome, err, "SourceControlFailed")
+	}
+	setPlacementReady(status, nil, consts.SuccessReconcile, message)
+	setSyncedCondition(&status.Conditions, 0, held)
+	return outcome
+}
+
+// removePlacement will delete Glue Job of placement removed from the spec, unless it's in one of locations,
+// and its uploaded objects, unless they are in use. It returns true if the placement status must be kept,
+// because deletion is only planned
+func (r *GlueJobReconciler) removePlacement(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob,
+	pj *placementGlueJob, policy, held string, locations, inUse map[string]struct{}) (bool, error) {
+	status := &pj.status
+	awsGlueJob, err := pj.job, pj.err
+	if err != nil {
+		setPlacementReady(status, err, "DeleteGlueJobFailed", "")
+		return false, fmt.Errorf("removed placement %s: %w", status.Name, err)
+	}
+	if _, ok := locations[status.AccountID+"/"+status.Region]; ok {
+		reqLogger.V(0).Info("Not deleting GlueJob on AWS used by another placement")
+		return false, nil
+	}
+	if conflictErr := awsGlueJob.OwnershipConflict(); conflictErr != nil {
+		reqLogger.V(0).Info("Not deleting GlueJob on AWS owned by someone else", "reason", conflictErr.Error())
+		return false, nil
+	}
+	if !awsGlueJob.JobExists() {
+		return false, nil
+	}
+	if policy != awsv1alpha1.ManagementPolicyFull {
+		status.PlannedChanges = awsGlueJob.DeletePlan()
+		setSyncedCondition(&status.Conditions, len(status.PlannedChanges), held)
+		r.recordPlannedChanges(gj, status.Name, status.PlannedChanges)
+		return true, nil
+	}
+	err = r.finalizeGlueJob(reqLogger, gj, awsGlueJob)
+	if err != nil {
+		setPlacementReady(status, err, "DeleteGlueJobFailed", "")
+		return false, fmt.Errorf("removed placement %s: %w", status.Name, err)
+	}
+	r.deleteUploaded(reqLogger, awsGlueJob, status, inUse)
+	return false, nil
+}
+
+// recordPlannedChanges will record event with the mutations, which were not made because of the management policy
+func (r *GlueJobReconciler) recordPlannedChanges(gj *awsv1alpha1.GlueJob, placement string, planned []string) {
+	if len(planned) == 0 {
+		return
+	}
+	message := strings.Join(planned, "; ")
+	if placement != defaultPlacement {
+		message = fmt.Sprintf("placement %s: %s", placement, message)
+	}
+	r.Recorder.Event(gj, corev1.EventTypeNormal, consts.ReasonChangesNotApplied, message)
+}
+
+// plannedChanges will return the number of planned changes in all placements
+func plannedChanges(statuses []awsv1alpha1.GlueJobPlacementStatus) int {
+	count := 0
+	for i := range statuses {
+		count += len(statuses[i].PlannedChanges)
+	}
+	return count
+}
+
+// setSyncedCondition will set Synced condition, which is false if there are planned changes,
+// which were not made for the held reason
+func setSyncedCondition(conditions *[]metav1.Condition, planned int, held string) {
+	condition := metav1.Condition{
+		Type:    consts.StatusSynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  consts.ReasonInSync,
+		Message: "Glue Job matches the spec",
+	}
+	if planned > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = consts.ReasonChangesNotApplied
+		condition.Message = fmt.Sprintf("%d planned changes are not applied due to %s", planned, held)
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// placementJob will return Glue Job of GlueJob in placement, status gets account and region of placement
+func (r *GlueJobReconciler) placementJob(gj *awsv1alpha1.GlueJob, placement *awsv1alpha1.GlueJobPlacement,
+	status *awsv1alpha1.GlueJobPlacementStatus) (*glue.Job, error) {
+	// AWS account, region and credentials come from AWSProviderConfig and placement
+	awsClients, err := r.AWS.ForPlacement(r.ctx, gj, placement)
+	if err != nil {
+		return nil, err
+	}
+	status.AccountID = awsClients.AccountID
+	status.Region = awsClients.Region
+
+	placementGJ := gj.DeepCopy()
+	err = r.resolveDefaultArguments(placementGJ)
+	if err != nil {
+		return nil, err
+	}
+	glue.ApplyPlacement(&placementGJ.Spec, placement)
+	script, commit, pinned, err := r.setScriptLocation(placementGJ, awsClients, status)
+	if err != nil {
+		return nil, err
+	}
+	modules, modulesLocation, modulesHash, err := r.setModulesLocation(placementGJ, awsClients, status)
+	if err != nil {
+		return nil, err
+	}
+	awsGlueJob, err := glue.NewJob(r.ctx, placementGJ, r.Config.Get(), awsClients)
+	if err != nil {
+		return nil, err
+	}
+	if pinned != "" {
+		awsGlueJob.SetPinnedScript(script, pinned)
+	} else if script != nil {
+		awsGlueJob.SetScript(script, commit)
+	}
+	if modulesLocation != "" {
+		awsGlueJob.SetModules(modulesLocation, modules, modulesHash)
+	}
+	return awsGlueJob, nil
+}
+
+// setScriptAvailableCondition will set ScriptAvailable condition, which is false if any of artifacts is missing,
+// and unknown if artifacts couldn't be checked
+func setScriptAvailableCondition(conditions *[]metav1.Condition, missing []string, err error) {
+	condition := metav1.Condition{
+		Type:    consts.StatusScriptAvailable,
+		Status:  metav1.ConditionTrue,
+		Reason:  consts.ReasonArtifactsFound,
+		Message: "Script and dependencies exist in S3",
+	}
+	switch {
+	case len(missing) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = consts.ReasonArtifactsMissing
+		condition.Message = "Missing in S3: " + strings.Join(missing, ", ")
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = consts.ReasonArtifactsCheckFailed
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// missingArtifacts will return missing artifacts of all placements
+func missingArtifacts(statuses []awsv1alpha1.GlueJobPlacementStatus) []string {
+	missing := make([]string, 0)
+	for i := range statuses {
+		for _, uri := range statuses[i].MissingArtifacts {
+			if !slices.Contains(missing, uri) {
+				missing = append(missing, uri)
+			}
+		}
+	}
+	return missing
+}
+
+// artifactsCheckErr will return error of the first placement, which artifacts couldn't be checked
+func artifactsCheckErr(statuses []awsv1alpha1.GlueJobPlacementStatus) error {
+	for i := range statuses {
+		condition := meta.FindStatusCondition(statuses[i].Conditions, consts.StatusScriptAvailable)
+		if condition != nil && condition.Status == metav1.ConditionUnknown {
+			return fmt.Errorf("%s", condition.Message)
+		}
+	}
+	return nil
+}
+
+// placementSuffix will return suffix identifying placement in messages, it's empty for the default placement
+func placementSuffix(placement string) string {
+	if placement == defaultPlacement {
+		return ""
+	}
+	return fmt.Sprintf(" in placement %s", placement)
+}
+
+// setPlacementReady will set Ready condition of placement
+func setPlacementReady(status *awsv1alpha1.GlueJobPlacementStatus, err error, reason, message string) {
+	condition := metav1.Condition{
+		Type:    consts.StatusReady,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awsglue "github.com/aws/aws-sdk-go-v2/service/glue"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+func TestManagementPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		observeOnly bool
+		policy      string
+		expected    string
+	}{
+		{name: "default", expected: awsv1alpha1.ManagementPolicyFull},
+		{name: "GlueJob policy", policy: awsv1alpha1.ManagementPolicyNoDelete, expected: awsv1alpha1.ManagementPolicyNoDelete},
+		{
+			name:        "operator observe only overrides GlueJob policy",
+			observeOnly: true,
+			policy:      awsv1alpha1.ManagementPolicyFull,
+			expected:    awsv1alpha1.ManagementPolicyObserveOnly,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &GlueJobReconciler{Config: config.StaticStore(config.OperatorConfig{ObserveOnly: tt.observeOnly})}
+			gj := &awsv1alpha1.GlueJob{Spec: awsv1alpha1.GlueJobSpec{ManagementPolicy: tt.policy}}
+			if policy := r.managementPolicy(gj); policy != tt.expected {
+				t.Errorf("expected policy %s, got %s", tt.expected, policy)
+			}
+		})
+	}
+}
+
+func TestPlannedChanges(t *testing.T) {
+	statuses := []awsv1alpha1.GlueJobPlacementStatus{
+		{Name: "primary", PlannedChanges: []string{"update Description", "add tag team"}},
+		{Name: "dr"},
+		{Name: "removed", PlannedChanges: []string{"delete Glue Job"}},
+	}
+	if count := plannedChanges(statuses); count != 3 {
+		t.Errorf("expected 3 planned changes, got %d", count)
+	}
+}
+
+func TestSetSyncedCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		planned int
+		status  metav1.ConditionStatus
+		reason  string
+	}{
+		{name: "in sync", status: metav1.ConditionTrue, reason: consts.ReasonInSync},
+		{name: "changes not applied", planned: 2, status: metav1.ConditionFalse, reason: consts.ReasonChangesNotApplied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := make([]metav1.Condition, 0)
+			setSyncedCondition(&conditions, tt.planned, "management policy ObserveOnly")
+			condition := meta.FindStatusCondition(conditions, consts.StatusSynced)
+			if condition == nil || condition.Status != tt.status || condition.Reason != tt.reason {
+				t.Errorf("expected %s condition with reason %s, got %v", tt.status, tt.reason, condition)
+			}
+		})
+	}
+}
+
+// fakeGlue is a stand-in of Glue and S3 endpoints with single Glue Job without tags
+type fakeGlue struct {
+	// exists is true, if the Glue Job exists
+	exists bool
+	// created counts created Glue Jobs
+	created int
+}
+
+// newFakeGlueClients will return clients of the stand-in endpoints
+func newFakeGlueClients(t *testing.T, fake *fakeGlue) *awsclient.Clients {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+	creds := credentials.NewStaticCredentialsProvider("key", "secret", "")
+	return &awsclient.Clients{
+		Glue: awsglue.New(awsglue.Options{
+			Region: "eu-west-1", BaseEndpoint: aws.String(server.URL), Credentials: creds, RetryMaxAttempts: 1,
+		}),
+		S3: s3.New(s3.Options{
+			Region: "eu-west-1", BaseEndpoint: aws.String(server.URL), Credentials: creds, RetryMaxAttempts: 1,
+			UsePathStyle: true,
+		}),
+		AccountID: "123456789012",
+		Region:    "eu-west-1",
+	}
+}
+
+func (f *fakeGlue) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+	switch target := r.Header.Get("X-Amz-Target"); {
+	case target == "":
+		// S3 objects exist
+		w.Header().Set("ETag", `"etag"`)
+	case target == "AWSGlue.GetJob" && !f.exists:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"__type":"EntityNotFoundException","Message":"Job not found"}`)
+	case target == "AWSGlue.GetJob":
+		fmt.Fprint(w, `{"Job":{"Name":"prod-team-a-etl","Command":{"Name":"glueetl","ScriptLocation":"s3://scripts/etl.py"}}}`)
+	case target == "AWSGlue.CreateJob" && f.exists:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"__type":"AlreadyExistsException","Message":"Job already exists"}`)
+	case target == "AWSGlue.CreateJob":
+		f.exists = true
+		f.created++
+		fmt.Fprint(w, `{"Name":"prod-team-a-etl"}`)
+	default:
+		fmt.Fprint(w, `{}`)
+	}
+}
+
+func TestReconcilePlacementMissing(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		exists  bool
+		created int
+		missing bool
+		events  int
+	}{
+		{name: "recreate", policy: awsv1alpha1.MissingJobPolicyRecreate, created: 1, events: 1},
+		{name: "mark missing", policy: awsv1alpha1.MissingJobPolicyMarkMissing, missing: true, events: 1},
+		{name: "job without owner tag isn't missing", policy: awsv1alpha1.MissingJobPolicyMarkMissing, exists: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.OperatorConfig{ClusterID: "prod"}
+			recorder := record.NewFakeRecorder(10)
+			r := &GlueJobReconciler{ctx: context.Background(), Config: config.StaticStore(cfg), Recorder: recorder}
+			gj := &awsv1alpha1.GlueJob{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "etl"},
+				Spec:       awsv1alpha1.GlueJobSpec{MissingJobPolicy: tt.policy},
+			}
+			gj.Spec.Command.Name = "glueetl"
+			gj.Spec.Command.ScriptLocation = "s3://scripts/etl.py"
+			fake := &fakeGlue{exists: tt.exists}
+			clients := newFakeGlueClients(t, fake)
+			pj := &placementGlueJob{
+				placement: &awsv1alpha1.GlueJobPlacement{Name: defaultPlacement},
+				status:    awsv1alpha1.GlueJobPlacementStatus{Name: defaultPlacement, Exists: true},
+			}
+
+			// the second reconcile finds the recreated Glue Job or the Glue Job still missing
+			for i := 0; i < 2; i++ {
+				var err error
+				pj.job, err = glue.NewJob(r.ctx, gj, cfg, clients)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				outcome := r.reconcilePlacement(logr.Discard(), gj, pj, awsv1alpha1.ManagementPolicyFull, "", false)
+				if outcome.err != nil {
+					t.Fatalf("unexpected error: %v", outcome.err)
+				}
+				if outcome.missing != tt.missing {
+					t.Errorf("expected missing %v, got %v", tt.missing, outcome.missing)
+				}
+			}
+			if fake.created != tt.created || int(pj.status.Recreations) != tt.created {
+				t.Errorf("expected %d recreations, got %d created and %d in status",
+					tt.created, fake.created, pj.status.Recreations)
+			}
+			events := 0
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, consts.ReasonMissing) {
+					events++
+				}
+			}
+			if events != tt.events {
+				t.Errorf("expected %d %s events, got %d", tt.events, consts.ReasonMissing, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+// scriptSource will return the script of GlueJob from its script source, the file name of the script
+// and the commit of Git repository the script comes from, if any
+func (r *GlueJobReconciler) scriptSource(gj *awsv1alpha1.GlueJob) ([]byte, string, string, error) {
+	source := gj.Spec.Command.ScriptSource
+	switch {
+	case source.GitRef != nil:
+		key := types.NamespacedName{Namespace: gj.Namespace, Name: source.GitRef.Name}
+		script, commit, err := r.ScriptSources.File(key, source.GitRef.File)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return script, path.Base(source.GitRef.File), commit, nil
+	case source.ConfigMapKeyRef == nil:
+		fileName := "script.py"
+		if strings.EqualFold(gj.Spec.DefaultArguments["--job-language"], "scala") {
+			fileName = "script.scala"
+		}
+		return []byte(source.Inline), fileName, "", nil
+	}
+	ref := source.ConfigMapKeyRef
+	configMap := &corev1.ConfigMap{}
+	err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: gj.Namespace, Name: ref.Name}, configMap)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get ConfigMap %s with script: %w", ref.Name, err)
+	}
+	if script, ok := configMap.Data[ref.Key]; ok {
+		return []byte(script), ref.Key, "", nil
+	}
+	if script, ok := configMap.BinaryData[ref.Key]; ok {
+		return script, ref.Key, "", nil
+	}
+	return nil, "", "", fmt.Errorf("ConfigMap %s has no script key %s", ref.Name, ref.Key)
+}
+
+// setScriptLocation will set the script location of GlueJob with script source or pinned script version
+// to the content addressed S3 path in the script bucket. It returns the script, which must be uploaded to the location,
+// the commit of Git repository the script comes from and the pinned script version. The script is nil,
+// if the placement status records the copy of the same pinned version
+func (r *GlueJobReconciler) setScriptLocation(gj *awsv1alpha1.GlueJob, awsClients *awsclient.Clients,
+	status *awsv1alpha1.GlueJobPlacementStatus) ([]byte, string, string, error) {
+	command := &gj.Spec.Command
+	if command.ScriptSource == nil && command.ScriptVersionID == "" {
+		return nil, "", "", nil
+	}
+	// the script isn't needed to delete Glue Job, its ConfigMap may be deleted already
+	if !gj.DeletionTimestamp.IsZero() {
+		command.ScriptLocation = status.ScriptObject
+		return nil, "", "", nil
+	}
+	cfg := r.Config.Get()
+	bucket := scriptBucket(cfg, awsClients)
+	var script []byte
+	var fileName, commit, pinned string
+	var err error
+	if command.ScriptSource != nil {
+		script, fileName, commit, err = r.scriptSource(gj)
+	} else {
+		pinned = glue.PinnedScriptVersion(command.ScriptLocation, command.ScriptVersionID)
+		if status.ScriptObject != "" && status.PinnedScript == pinned {
+			command.ScriptLocation = status.ScriptObject
+			return nil, "", pinned, nil
+		}
+		// the version is read with the operator credentials, so only buckets meant for it can be read
+		sourceBucket, _, _ := strings.Cut(strings.TrimPrefix(command.ScriptLocation, "s3://"), "/")
+		if !sourceBucketAllowed(cfg, awsClients, sourceBucket) {
+			return nil, "", "", fmt.Errorf("bucket %s of pinned script isn't the script bucket or one of source buckets",
+				sourceBucket)
+		}
+		script, fileName, err = glue.PinnedScript(r.ctx, awsClients.S3, command.ScriptLocation, command.ScriptVersionID)
+		if bucket == "" {
+			// pinned version is copied next to the script, if the script bucket isn't configured
+			bucket = sourceBucket
+		}
+	}
+	if err != nil {
+		return nil, "", "", err
+	}
+	if bucket == "" {
+		return nil, "", "", fmt.Errorf("script bucket isn't configured for script source")
+	}
+	command.ScriptLocation = glue.ScriptObject(bucket, cfg.ScriptPrefix, gj.Status.ResolvedName, fileName, script)
+	return script, commit, pinned, nil
+}
+
+// scriptBucket will return the bucket of objects uploaded by the operator, empty if it isn't configured
+func scriptBucket(cfg config.OperatorConfig, awsClients *awsclient.Clients) string {
+	if awsClients.ScriptBucket != "" {
+		return awsClients.ScriptBucket
+	}
+	return cfg.ScriptBucket
+}
+
+// sourceBucketAllowed will return true, if objects of bucket may be read with the operator credentials
+// and copied to the script bucket
+func sourceBucketAllowed(cfg config.OperatorConfig, awsClients *awsclient.Clients, bucket string) bool {
+	return bucket == scriptBucket(cfg, awsClients) || slices.Contains(cfg.SourceBuckets, bucket)
+}
+
+// checkScriptVersion will record the version of the script, which Glue Job runs, in the placement status.
+// It returns true, if the script changed since the last reconcile
+func (r *GlueJobReconciler) checkScriptVersion(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob, awsGlueJob *glue.Job,
+	placement string, status *awsv1alpha1.GlueJobPlacementStatus) bool {
+	version, err := awsGlueJob.ScriptVersion()
+	if err != nil {
+		reqLogger.V(1).Info("Failed to get version of script", "reason", err.Error())
+		return false
+	}
+	changed := scriptChanged(status, version)
+	if changed {
+		reqLogger.V(0).Info("Script changed", "script", awsGlueJob.ScriptLocation(),
+			"etag", version.ETag, "versionId", version.VersionID)
+		r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonScriptChanged,
+			"Script %s changed (ETag %s, version %s)%s", awsGlueJob.ScriptLocation(), version.ETag,
+			version.VersionID, placementSuffix(placement))
+	}
+	status.ScriptETag = version.ETag
+	status.ScriptVersionID = version.VersionID
+	return changed
+}
+
+// scriptChanged will return true, if the script version differs from the version recorded in the placement status.
+// The first recorded version isn't a change
+func scriptChanged(status *awsv1alpha1.GlueJobPlacementStatus, version glue.ScriptVersion) bool {
+	return status.ScriptETag != "" &&
+		(status.ScriptETag != version.ETag || status.ScriptVersionID != version.VersionID)
+}
+
+// startSmokeTest will start the smoke test run of Glue Job, failures are only recorded as events,
+// because the run isn't retried
+func (r *GlueJobReconciler) startSmokeTest(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob, awsGlueJob *glue.Job,
+	placement string, status *awsv1alpha1.GlueJobPlacementStatus) {
+	runID, err := awsGlueJob.StartJobRun(gj.Spec.SmokeTest.Arguments)
+	if err != nil {
+		reqLogger.V(0).Error(err, "Failed to start smoke test")
+		r.Recorder.Eventf(gj, corev1.EventTypeWarning, consts.ReasonSmokeTestFailed,
+			"Failed to start smoke test: %s%s", err.Error(), placementSuffix(placement))
+		return
+	}
+	status.SmokeTestRunID = runID
+	reqLogger.V(0).Info("Started smoke test", "runId", runID)
+	r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonSmokeTestStarted,
+		"Started smoke test run %s%s", runID, placementSuffix(placement))
+}
+
+// deleteSuperseded will delete the object (script or modules bundle) uploaded by the operator, which Glue Job
+// doesn't use anymore, and record the uploaded object in use
+func (r *GlueJobReconciler) deleteSuperseded(reqLogger logr.Logger, awsGlueJob *glue.Job, object *string,
+	uploaded string) {
+	if *object != "" && *object != uploaded {
+		err := awsGlueJob.DeleteUploaded(*object)
+		if err != nil {
+			// superseded object is deleted on the next reconcile
+			reqLogger.V(0).Error(err, "Failed to delete superseded object", "object", *object)
+			return
+		}
+		reqLogger.V(1).Info("Deleted superseded object", "object", *object)
+	}
+	*object = uploaded
+}
+
+// deleteUploaded will delete the objects uploaded by the operator for deleted Glue Job, except the objects
+// in use by other Glue Jobs of GlueJob
+func (r *GlueJobReconciler) deleteUploaded(reqLogger logr.Logger, awsGlueJob *glue.Job,
+	status *awsv1alpha1.GlueJobPlacementStatus, inUse map[string]struct{}) {
+	for _, object := range []*string{&status.ScriptObject, &status.ModulesObject} {
+		if *object == "" {
+			continue
+		}
+		if _, ok := inUse[*object]; ok {
+			reqLogger.V(1).Info("Not deleting uploaded object used by another placement", "object", *object)
+			*object = ""
+			continue
+		}
+		err := awsGlueJob.DeleteUploaded(*object)
+		if err != nil {
+			reqLogger.V(0).Error(err, "Failed to delete uploaded object", "object", *object)
+			continue
+		}
+		*object = ""
+	}
+}
+
+// glueJobsForConfigMap will return requests for GlueJobs, which script, modules or default arguments are in ConfigMap
+func (r *GlueJobReconciler) glueJobsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := r.List(ctx, glueJobs, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{awsv1alpha1.ConfigMapIndex: obj.GetName()})
+	if err != nil {
+		log.FromContext(ctx).V(0).Error(err, "Failed to list GlueJobs referencing ConfigMap",
+			"namespace", obj.GetNamespace(), "configmap", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(glueJobs.Items))
+	for i := range glueJobs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&glueJobs.Items[i])})
+	}
+	return requests
+}
+
+// glueJobsForScriptSource will return requests for GlueJobs, which script is in GlueScriptSource
+func (r *GlueJobReconciler) glueJobsForScriptSource(ctx context.Context, obj client.Object) []reconcile.Request {
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := r.List(ctx, glueJobs, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{awsv1alpha1.ScriptSourceIndex: obj.GetName()})
+	if err != nil {
+		log.FromContext(ctx).V(0).Error(err, "Failed to list GlueJobs referencing GlueScriptSource",
+			"namespace", obj.GetNamespace(), "gluescriptsource", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(glueJobs.Items))
+	for i := range glueJobs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&glueJobs.Items[i])})
+	}
+	return requests
+}
+
+// scriptCommitChangedPredicate will pass GlueScriptSources, which fetched new commit
+func scriptCommitChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSource, okOld := e.ObjectOld.(*awsv1alpha1.GlueScriptSource)
+			newSource, okNew := e.ObjectNew.(*awsv1alpha1.GlueScriptSource)
+			return okOld && okNew && oldSource.Status.Commit != newSource.Status.Commit
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+	}
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+func TestSetScriptLocation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = awsv1alpha1.AddToScheme(scheme)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "scripts"},
+		Data:       map[string]string{"etl.py": "print('etl')"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+	r := &GlueJobReconciler{
+		ctx:       context.Background(),
+		Client:    fakeClient,
+		APIReader: fakeClient,
+		Config:    config.NewStore(config.OperatorConfig{ScriptBucket: "scripts", ScriptPrefix: "glue"}, "", logr.Discard()),
+	}
+	deleted := metav1.Now()
+
+	tests := []struct {
+		name         string
+		source       *awsv1alpha1.GlueJobScriptSource
+		scriptBucket string
+		deletion     *metav1.Time
+		script       string
+		location     string
+		wantErr      bool
+	}{
+		{name: "script location", location: "s3://bucket/etl.py"},
+		{
+			name:     "inline",
+			source:   &awsv1alpha1.GlueJobScriptSource{Inline: "print('inline')"},
+			script:   "print('inline')",
+			location: glue.ScriptObject("scripts", "glue", "prod_team-a_etl", "script.py", []byte("print('inline')")),
+		},
+		{
+			name: "ConfigMap",
+			source: &awsv1alpha1.GlueJobScriptSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "scripts"}, Key: "etl.py"}},
+			scriptBucket: "placement",
+			script:       "print('etl')",
+			location:     glue.ScriptObject("placement", "glue", "prod_team-a_etl", "etl.py", []byte("print('etl')")),
+		},
+		{
+			name: "missing ConfigMap key",
+			source: &awsv1alpha1.GlueJobScriptSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "scripts"}, Key: "missing.py"}},
+			wantErr: true,
+		},
+		{
+			name:     "deleting uses uploaded object",
+			source:   &awsv1alpha1.GlueJobScriptSource{Inline: "print('inline')"},
+			deletion: &deleted,
+			location: "s3://scripts/glue/uploaded/script.py",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gj := &awsv1alpha1.GlueJob{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "etl", DeletionTimestamp: tt.deletion},
+				Status:     awsv1alpha1.GlueJobStatus{ResolvedName: "prod_team-a_etl"},
+			}
+			gj.Spec.Command.ScriptLocation = "s3://bucket/etl.py"
+			gj.Spec.Command.ScriptSource = tt.source
+			status := &awsv1alpha1.GlueJobPlacementStatus{ScriptObject: "s3://scripts/glue/uploaded/script.py"}
+			script, _, _, err := r.setScriptLocation(gj, &awsclient.Clients{ScriptBucket: tt.scriptBucket}, status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(script) != tt.script {
+				t.Errorf("expected script %q, got %q", tt.script, script)
+			}
+			if gj.Spec.Command.ScriptLocation != tt.location {
+				t.Errorf("expected location %s, got %s", tt.location, gj.Spec.Command.ScriptLocation)
+			}
+		})
+	}
+}
+
+func TestSetScriptLocationPinned(t *testing.T) {
+	r := &GlueJobReconciler{
+		ctx:    context.Background(),
+		Config: config.StaticStore(config.OperatorConfig{ScriptBucket: "scripts", SourceBuckets: []string{"ci"}}),
+	}
+	newGlueJob := func(location string) *awsv1alpha1.GlueJob {
+		gj := &awsv1alpha1.GlueJob{Status: awsv1alpha1.GlueJobStatus{ResolvedName: "prod_team-a_etl"}}
+		gj.Spec.Command.ScriptLocation = location
+		gj.Spec.Command.ScriptVersionID = "v2"
+		return gj
+	}
+	copied := "s3://scripts/glue-jobs-operator/scripts/prod_team-a_etl/0123/etl.py"
+
+	// the copy of the same version isn't read again, so S3 client isn't needed
+	gj := newGlueJob("s3://ci/etl.py")
+	status := &awsv1alpha1.GlueJobPlacementStatus{
+		ScriptObject: copied,
+		PinnedScript: glue.PinnedScriptVersion("s3://ci/etl.py", "v2"),
+	}
+	script, _, pinned, err := r.setScriptLocation(gj, &awsclient.Clients{}, status)
+	if err != nil || script != nil || pinned != status.PinnedScript {
+		t.Errorf("expected copy of %s to be reused, got %d bytes of %s: %v", status.PinnedScript, len(script), pinned, err)
+	}
+	if gj.Spec.Command.ScriptLocation != copied {
+		t.Errorf("expected location %s, got %s", copied, gj.Spec.Command.ScriptLocation)
+	}
+
+	gj = newGlueJob("s3://team-b-secrets/etl.py")
+	status = &awsv1alpha1.GlueJobPlacementStatus{}
+	if _, _, _, err = r.setScriptLocation(gj, &awsclient.Clients{}, status); err == nil {
+		t.Error("expected error of pinned script in bucket, which isn't allowed")
+	}
+}
+
+func TestDeleteUploadedInUse(t *testing.T) {
+	r := &GlueJobReconciler{}
+	status := &awsv1alpha1.GlueJobPlacementStatus{
+		ScriptObject:  "s3://scripts/glue/job/0123/script.py",
+		ModulesObject: "s3://scripts/glue/job/4567/modules.zip",
+	}
+	inUse := map[string]struct{}{status.ScriptObject: {}, status.ModulesObject: {}}
+	// objects in use are not deleted, so the Glue Job isn't needed
+	r.deleteUploaded(logr.Discard(), nil, status, inUse)
+	if status.ScriptObject != "" || status.ModulesObject != "" {
+		t.Errorf("expected objects in use to be forgotten, got %s, %s", status.ScriptObject, status.ModulesObject)
+	}
+}
+
+func TestScriptChanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   awsv1alpha1.GlueJobPlacementStatus
+		version  glue.ScriptVersion
+		expected bool
+	}{
+		{name: "first version", version: glue.ScriptVersion{ETag: "a", VersionID: "1"}},
+		{
+			name:    "same version",
+			status:  awsv1alpha1.GlueJobPlacementStatus{ScriptETag: "a", ScriptVersionID: "1"},
+			version: glue.ScriptVersion{ETag: "a", VersionID: "1"},
+		},
+		{
+			name:     "changed ETag",
+			status:   awsv1alpha1.GlueJobPlacementStatus{ScriptETag: "a"},
+			version:  glue.ScriptVersion{ETag: "b"},
+			expected: true,
+		},
+		{
+			name:     "same content uploaded again",
+			status:   awsv1alpha1.GlueJobPlacementStatus{ScriptETag: "a", ScriptVersionID: "1"},
+			version:  glue.ScriptVersion{ETag: "a", VersionID: "2"},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scriptChanged(&tt.status, tt.version); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
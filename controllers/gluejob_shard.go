@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/metrics"
+)
+
+// shardHandoverRequeue is how often GlueJob claimed by another shard is checked for release
+const shardHandoverRequeue = 30 * time.Second
+
+// reconcileShard will return true, if GlueJob is reconciled by the shard of this instance.
+// GlueJob in the shard is claimed by recording the shard in annotation. GlueJob claimed by another
+// shard is taken over only after the other shard released it or its leader election lease expired,
+// so two instances never reconcile the same GlueJob while shard config changes. GlueJob, which
+// left the shard, is released
+func (r *GlueJobReconciler) reconcileShard(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob) (bool, ctrl.Result, error) {
+	cfg := r.Config.Get()
+	if !cfg.Sharded() {
+		return true, ctrl.Result{}, nil
+	}
+	shard := cfg.ShardName
+	claimedBy := gj.Annotations[awsv1alpha1.ShardAnnotation]
+	if !cfg.InShard(gj.Namespace, gj.Name, gj.Labels) {
+		r.setShardOwned(client.ObjectKeyFromObject(gj), false)
+		if claimedBy != shard {
+			return false, ctrl.Result{}, nil
+		}
+		reqLogger.V(0).Info("Releasing GlueJob, which left the shard", "shard", shard)
+		return false, ctrl.Result{}, r.setShardAnnotation(gj, "")
+	}
+	if claimedBy != "" && claimedBy != shard {
+		active, err := r.shardActive(claimedBy)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		if active {
+			reqLogger.V(1).Info("Waiting for another shard to release GlueJob", "shard", claimedBy)
+			return false, ctrl.Result{RequeueAfter: shardHandoverRequeue}, nil
+		}
+		reqLogger.V(0).Info("Taking over GlueJob from inactive shard", "shard", claimedBy)
+	}
+	if claimedBy != shard {
+		err := r.setShardAnnotation(gj, shard)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+	}
+	r.setShardOwned(client.ObjectKeyFromObject(gj), true)
+	return true, ctrl.Result{}, nil
+}
+
+// setShardAnnotation will set the shard annotation of GlueJob, empty shard removes it
+func (r *GlueJobReconciler) setShardAnnotation(gj *awsv1alpha1.GlueJob, shard string) error {
+	patch := client.MergeFrom(gj.DeepCopy())
+	if shard == "" {
+		delete(gj.Annotations, awsv1alpha1.ShardAnnotation)
+	} else {
+		if gj.Annotations == nil {
+			gj.Annotations = make(map[string]string)
+		}
+		gj.Annotations[awsv1alpha1.ShardAnnotation] = shard
+	}
+	return r.Patch(r.ctx, gj, patch)
+}
+
+// shardActive will return true, if the leader election lease of shard is held and not expired.
+// Shards without leader election have no lease and are never considered active
+func (r *GlueJobReconciler) shardActive(shard string) (bool, error) {
+	if r.LeaseNamespace == "" {
+		return false, nil
+	}
+	lease := &coordinationv1.Lease{}
+	key := client.ObjectKey{Namespace: r.LeaseNamespace, Name: config.ShardLeaderElectionID(shard)}
+	err := r.APIReader.Get(r.ctx, key, lease)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" ||
+		lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false, nil
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return time.Now().Before(expiry), nil
+}
+
+// setShardOwned will record, if GlueJob is reconciled by the shard of this instance
+func (r *GlueJobReconciler) setShardOwned(key types.NamespacedName, owned bool) {
+	cfg := r.Config.Get()
+	if !cfg.Sharded() {
+		return
+	}
+	r.shardMu.Lock()
+	defer r.shardMu.Unlock()
+	if r.shardOwned == nil {
+		r.shardOwned = make(map[types.NamespacedName]struct{})
+	}
+	if owned {
+		r.shardOwned[key] = struct{}{}
+	} else {
+		delete(r.shardOwned, key)
+	}
+	metrics.ShardOwnedGlueJobs.WithLabelValues(cfg.ShardName).Set(float64(len(r.shardOwned)))
+}
+
+// shardPredicate filters out events of GlueJobs, which are neither in the shard nor claimed by it
+func shardPredicate(cfg config.OperatorConfig) predicate.Predicate {
+	relevant := func(obj client.Object) bool {
+		return !cfg.Sharded() || cfg.InShard(obj.GetNamespace(), obj.GetName(), obj.GetLabels()) ||
+			obj.GetAnnotations()[awsv1alpha1.ShardAnnotation] == cfg.ShardName
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return relevant(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return relevant(e.ObjectOld) || relevant(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return relevant(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return relevant(e.Object) },
+	}
+}
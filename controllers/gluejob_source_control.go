@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+// syncSourceControl will pull Glue Job from or push it to source control once per value of the annotations,
+// the synced commit is recorded in the placement status
+func (r *GlueJobReconciler) syncSourceControl(reqLogger logr.Logger, gj *awsv1alpha1.GlueJob, awsGlueJob *glue.Job,
+	placement string, status *awsv1alpha1.GlueJobPlacementStatus) error {
+	pull, push := sourceControlRequests(gj, status)
+	if pull == "" && push == "" {
+		return nil
+	}
+	if gj.Spec.SourceControlDetails == nil {
+		return fmt.Errorf("source control action requested, but sourceControlDetails isn't set")
+	}
+	token, err := r.sourceControlToken(gj)
+	if err != nil {
+		return err
+	}
+	if pull != "" {
+		commit, err := awsGlueJob.PullFromSourceControl(token)
+		if err != nil {
+			return err
+		}
+		status.SourceControlPull = pull
+		status.SourceControlCommit = commit
+		reqLogger.V(0).Info("Pulled Glue Job from source control", "commit", commit)
+		r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonSourceControlPulled,
+			"Pulled Glue Job from source control at commit %s%s", commit, placementSuffix(placement))
+	}
+	if push != "" {
+		commit, err := awsGlueJob.PushToSourceControl(token)
+		if err != nil {
+			return err
+		}
+		status.SourceControlPush = push
+		status.SourceControlCommit = commit
+		reqLogger.V(0).Info("Pushed Glue Job to source control", "commit", commit)
+		r.Recorder.Eventf(gj, corev1.EventTypeNormal, consts.ReasonSourceControlPushed,
+			"Pushed Glue Job to source control at commit %s%s", commit, placementSuffix(placement))
+	}
+	return nil
+}
+
+// sourceControlRequests will return the values of pull and push annotations, which the Glue Job of placement
+// wasn't synced for yet, empty if the action isn't requested
+func sourceControlRequests(gj *awsv1alpha1.GlueJob, status *awsv1alpha1.GlueJobPlacementStatus) (string, string) {
+	pull := gj.Annotations[awsv1alpha1.SourceControlPullAnnotation]
+	if pull == status.SourceControlPull {
+		pull = ""
+	}
+	push := gj.Annotations[awsv1alpha1.SourceControlPushAnnotation]
+	if push == status.SourceControlPush {
+		push = ""
+	}
+	return pull, push
+}
+
+// sourceControlToken will return the auth token of source control from the Secret, empty if it isn't set
+func (r *GlueJobReconciler) sourceControlToken(gj *awsv1alpha1.GlueJob) (string, error) {
+	ref := gj.Spec.SourceControlDetails.AuthTokenSecretRef
+	if ref == nil {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	err := r.APIReader.Get(r.ctx, client.ObjectKey{Namespace: gj.Namespace, Name: ref.Name}, secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s with source control token: %w", ref.Name, err)
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("Secret %s has no source control token key %s", ref.Name, ref.Key)
+	}
+	return string(token), nil
+}
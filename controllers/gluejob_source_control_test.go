@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+)
+
+func TestSourceControlRequests(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		status      awsv1alpha1.GlueJobPlacementStatus
+		pull        string
+		push        string
+	}{
+		{name: "no annotations"},
+		{
+			name:        "new pull",
+			annotations: map[string]string{awsv1alpha1.SourceControlPullAnnotation: "1"},
+			pull:        "1",
+		},
+		{
+			name:        "pulled already",
+			annotations: map[string]string{awsv1alpha1.SourceControlPullAnnotation: "1"},
+			status:      awsv1alpha1.GlueJobPlacementStatus{SourceControlPull: "1"},
+		},
+		{
+			name:        "pull again with new value",
+			annotations: map[string]string{awsv1alpha1.SourceControlPullAnnotation: "2"},
+			status:      awsv1alpha1.GlueJobPlacementStatus{SourceControlPull: "1"},
+			pull:        "2",
+		},
+		{
+			name: "pushed already, new pull",
+			annotations: map[string]string{
+				awsv1alpha1.SourceControlPullAnnotation: "2",
+				awsv1alpha1.SourceControlPushAnnotation: "1",
+			},
+			status: awsv1alpha1.GlueJobPlacementStatus{SourceControlPull: "1", SourceControlPush: "1"},
+			pull:   "2",
+		},
+		{
+			name:   "removed annotations",
+			status: awsv1alpha1.GlueJobPlacementStatus{SourceControlPull: "1", SourceControlPush: "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gj := &awsv1alpha1.GlueJob{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			pull, push := sourceControlRequests(gj, &tt.status)
+			if pull != tt.pull || push != tt.push {
+				t.Errorf("expected pull %q and push %q, got %q and %q", tt.pull, tt.push, pull, push)
+			}
+		})
+	}
+}
+
+func TestSyncSourceControlOnce(t *testing.T) {
+	r := &GlueJobReconciler{}
+	gj := &awsv1alpha1.GlueJob{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		awsv1alpha1.SourceControlPullAnnotation: "1",
+		awsv1alpha1.SourceControlPushAnnotation: "1",
+	}}}
+	// synced annotation values don't need the Glue Job
+	status := &awsv1alpha1.GlueJobPlacementStatus{SourceControlPull: "1", SourceControlPush: "1"}
+	if err := r.syncSourceControl(logr.Discard(), gj, nil, defaultPlacement, status); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	gj.Annotations[awsv1alpha1.SourceControlPushAnnotation] = "2"
+	if err := r.syncSourceControl(logr.Discard(), gj, nil, defaultPlacement, status); err == nil {
+		t.Error("expected error of push without sourceControlDetails")
+	}
+	if status.SourceControlPush != "1" {
+		t.Errorf("expected failed push not to be recorded, got %s", status.SourceControlPush)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/consts"
+	"github.com/90poe/glue-jobs-operator/internal/gitsource"
+)
+
+const (
+	// defaultScriptSourceInterval is the poll interval of GlueScriptSources without interval
+	defaultScriptSourceInterval = 5 * time.Minute
+	// minScriptSourceInterval is the shortest poll interval, the CRD rejects shorter intervals
+	minScriptSourceInterval = 30 * time.Second
+)
+
+// GlueScriptSourceReconciler polls Git repositories of GlueScriptSources
+type GlueScriptSourceReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// Config holds the operator config, which is reloaded on changes of the config file
+	Config *config.Store
+	// Recorder records events of GlueScriptSources
+	Recorder record.EventRecorder
+	// APIReader reads Secrets with credentials bypassing the cache, so Secrets of the cluster aren't cached
+	APIReader client.Reader
+	// ScriptSources are the fetched files of GlueScriptSources, GlueJobReconciler uploads scripts from them
+	ScriptSources *gitsource.Cache
+}
+
+//+kubebuilder:rbac:groups=aws.90poe.io,resources=gluescriptsources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=aws.90poe.io,resources=gluescriptsources/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile will fetch the files of the ref of GlueScriptSource, when the ref points to a new commit
+func (r *GlueScriptSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqLogger := log.FromContext(ctx).WithValues("gluescriptsources", req.NamespacedName)
+
+	watched, err := r.Config.Get().WatchesNamespace(ctx, r, req.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !watched {
+		reqLogger.V(1).Info("GlueScriptSource namespace isn't watched. Ignoring.")
+		return ctrl.Result{}, nil
+	}
+
+	source := &awsv1alpha1.GlueScriptSource{}
+	err = r.Get(ctx, req.NamespacedName, source)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			reqLogger.V(1).Info("GlueScriptSource resource not found. Ignoring since object must be deleted.")
+			r.ScriptSources.Delete(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		reqLogger.V(0).Error(err, "Failed to get GlueScriptSource.")
+		return ctrl.Result{}, err
+	}
+	interval := source.Spec.Interval.Duration
+	if interval <= 0 {
+		interval = defaultScriptSourceInterval
+	}
+	if interval < minScriptSourceInterval {
+		interval = minScriptSourceInterval
+	}
+
+	original := source.Status.DeepCopy()
+	snapshot, fetchErr := r.fetch(ctx, source)
+	if fetchErr == nil {
+		if snapshot.Commit != source.Status.Commit {
+			reqLogger.V(0).Info("Fetched new commit", "commit", snapshot.Commit)
+			r.Recorder.Eventf(source, corev1.EventTypeNormal, consts.ReasonFetched,
+				"Fetched commit %s of %s", snapshot.Commit, source.Spec.Ref)
+		}
+		source.Status.Commit = snapshot.Commit
+		source.Status.ObservedGeneration = source.Generation
+	} else {
+		reqLogger.V(0).Error(fetchErr, "Failed to fetch GlueScriptSource")
+	}
+	setScriptSourceReady(source, fetchErr)
+	if !apiequality.Semantic.DeepEqual(original, &source.Status) {
+		err = r.Status().Update(ctx, source)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if fetchErr != nil {
+		return ctrl.Result{}, fetchErr
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// fetch will return the snapshot of GlueScriptSource files, the repository is cloned only,
+// if the ref points to another commit than the cached snapshot or the spec changed
+func (r *GlueScriptSourceReconciler) fetch(ctx context.Context,
+	source *awsv1alpha1.GlueScriptSource) (*gitsource.Snapshot, error) {
+	var secretData map[string][]byte
+	if source.Spec.SecretRef != nil {
+		secret := &corev1.Secret{}
+		err := r.APIReader.Get(ctx, client.ObjectKey{Namespace: source.Namespace, Name: source.Spec.SecretRef.Name}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s with credentials: %w", source.Spec.SecretRef.Name, err)
+		}
+		secretData = secret.Data
+	}
+	auth, err := gitsource.Auth(secretData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Secret %s with credentials: %w", source.Spec.SecretRef.Name, err)
+	}
+	ref, revision, err := gitsource.ResolveRef(ctx, source.Spec.URL, source.Spec.Ref, auth)
+	if err != nil {
+		return nil, err
+	}
+	key := client.ObjectKeyFromObject(source)
+	cached := r.ScriptSources.Get(key)
+	if cached != nil && cached.Revision == revision && cached.Generation == source.Generation {
+		return cached, nil
+	}
+	snapshot, err := gitsource.Fetch(ctx, source.Spec.URL, ref, source.Spec.Path, auth)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Revision = revision
+	snapshot.Generation = source.Generation
+	r.ScriptSources.Set(key, snapshot)
+	return snapshot, nil
+}
+
+// setScriptSourceReady will set Ready condition of GlueScriptSource
+func setScriptSourceReady(source *awsv1alpha1.GlueScriptSource, err error) {
+	condition := metav1.Condition{
+		Type:               consts.StatusReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             consts.ReasonFetched,
+		Message:            fmt.Sprintf("Fetched commit %s", source.Status.Commit),
+		ObservedGeneration: source.Generation,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = consts.ReasonFetchFailed
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&source.Status.Conditions, condition)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GlueScriptSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&awsv1alpha1.GlueScriptSource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
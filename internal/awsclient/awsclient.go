@@ -0,0 +1,443 @@
+package awsclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	awsglue "github.com/aws/aws-sdk-go-v2/service/glue"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+// defaultSessionName is the role session name used, when AWSProviderConfig doesn't set one
+const defaultSessionName = "glue-jobs-operator"
+
+// Clients are AWS clients of single account and region
+type Clients struct {
+	// Config is AWS config with region and credentials of the account
+	Config aws.Config
+	// Glue is AWS Glue client
+	Glue *awsglue.Client
+	// S3 is AWS S3 client
+	S3 *s3.Client
+	// SecretsManager is AWS Secrets Manager client
+	SecretsManager *secretsmanager.Client
+	// SSM is AWS Systems Manager client
+	SSM *ssm.Client
+	// IAM is AWS IAM client
+	IAM *iam.Client
+	// AccountID is ID of the account
+	AccountID string
+	// Region is the region of the clients
+	Region string
+	// ScriptBucket is the bucket of scripts uploaded from script sources, empty means the operator default
+	ScriptBucket string
+}
+
+// cachedClients are Clients created for specific version of AWSProviderConfig
+type cachedClients struct {
+	resourceVersion string
+	// ready is closed, when clients are created or creation failed with err
+	ready   chan struct{}
+	clients *Clients
+	err     error
+}
+
+// Provider creates and caches AWS clients per AWSProviderConfig and per placement. Clients of the operator
+// credentials (without AWSProviderConfig) are cached under the empty key.
+type Provider struct {
+	client.Reader
+	// namespaces reads Namespaces without cache, so only get access to them is required
+	namespaces client.Reader
+	// defaults is the region and role of the operator credentials from the operator config
+	defaults awsv1alpha1.AWSProviderConfigSpec
+	mu       sync.Mutex
+	clients  map[string]*cachedClients
+	// placementKeys are the cache keys of clients of placements by GlueJob namespace, name and placement name
+	placementKeys map[string]string
+}
+
+// NewProvider will return a new Provider, which reads AWSProviderConfigs with reader and Namespaces with
+// uncached apiReader. Default AWS region and role of the operator config are used for GlueJobs without AWSProviderConfig
+func NewProvider(reader, apiReader client.Reader, cfg config.OperatorConfig) *Provider {
+	p := &Provider{
+		Reader:        reader,
+		namespaces:    apiReader,
+		clients:       make(map[string]*cachedClients),
+		placementKeys: make(map[string]string),
+	}
+	p.defaults.Region = cfg.AWSRegion
+	if cfg.AWSAssumeRoleARN != "" {
+		p.defaults.AssumeRole = &awsv1alpha1.AWSAssumeRole{
+			RoleARN:    cfg.AWSAssumeRoleARN,
+			ExternalID: cfg.AWSAssumeRoleExternalID,
+		}
+	}
+	return p
+}
+
+// ProviderConfigName will return name of AWSProviderConfig used by GlueJob,
+// empty name means the operator credentials and region are used
+func (p *Provider) ProviderConfigName(ctx context.Context, gj *awsv1alpha1.GlueJob) (string, error) {
+	if gj.Spec.ProviderConfigRef != nil {
+		return gj.Spec.ProviderConfigRef.Name, nil
+	}
+	ns := &corev1.Namespace{}
+	err := p.namespaces.Get(ctx, client.ObjectKey{Name: gj.Namespace}, ns)
+	if err != nil {
+		return "", fmt.Errorf("failed to get namespace %s: %w", gj.Namespace, err)
+	}
+	return ns.Annotations[awsv1alpha1.ProviderConfigAnnotation], nil
+}
+
+// ForGlueJob will return AWS clients for GlueJob
+func (p *Provider) ForGlueJob(ctx context.Context, gj *awsv1alpha1.GlueJob) (*Clients, error) {
+	name, err := p.ProviderConfigName(ctx, gj)
+	if err != nil {
+		return nil, err
+	}
+	return p.ForProviderConfig(ctx, name, gj.Namespace)
+}
+
+// ForProviderConfig will return AWS clients for AWSProviderConfig with name used by GlueJob in namespace,
+// or for the operator credentials if name is empty. Empty namespace means the operator itself
+// (e.g. the orphaned Glue Jobs garbage collector), which may use any AWSProviderConfig
+func (p *Provider) ForProviderConfig(ctx context.Context, name, namespace string) (*Clients, error) {
+	if name == "" {
+		return p.cached(ctx, name, "", p.defaults.DeepCopy())
+	}
+	key := "AWSProviderConfig " + name
+	providerConfig, err := p.providerConfig(ctx, name, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			p.forget(key)
+		}
+		return nil, err
+	}
+	return p.cached(ctx, key, providerConfig.ResourceVersion, &providerConfig.Spec)
+}
+
+// providerConfig will return AWSProviderConfig with name, if it allows GlueJobs in namespace to use it
+func (p *Provider) providerConfig(ctx context.Context, name, namespace string) (*awsv1alpha1.AWSProviderConfig, error) {
+	providerConfig := &awsv1alpha1.AWSProviderConfig{}
+	err := p.Get(ctx, client.ObjectKey{Name: name}, providerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWSProviderConfig %s: %w", name, err)
+	}
+	allowed := providerConfig.Spec.AllowedNamespaces
+	if namespace != "" && len(allowed) > 0 && !slices.Contains(allowed, namespace) {
+		return nil, fmt.Errorf("AWSProviderConfig %s isn't allowed in namespace %s", name, namespace)
+	}
+	return providerConfig, nil
+}
+
+// ForPlacement will return AWS clients for placement of GlueJob. Placement region and
+// assume role override AWSProviderConfig of the placement or of GlueJob, the role must be
+// allowed by the AWSProviderConfig
+func (p *Provider) ForPlacement(ctx context.Context, gj *awsv1alpha1.GlueJob,
+	placement *awsv1alpha1.GlueJobPlacement) (*Clients, error) {
+	name, spec, key, err := p.placementSpec(ctx, gj, placement)
+	if err != nil {
+		return nil, err
+	}
+	p.usePlacementKey(gj.Namespace+"/"+gj.Name+"/"+placement.Name, key)
+	if key == "" {
+		return p.ForProviderConfig(ctx, name, gj.Namespace)
+	}
+	return p.cached(ctx, key, "", spec)
+}
+
+// ForPlacementOverride will return AWS clients for placement of GlueJob, which overrides region or assume role,
+// nil if it doesn't. Unlike ForPlacement the clients aren't kept for the placement, so the garbage collector
+// can look into placements removed from GlueJobs
+func (p *Provider) ForPlacementOverride(ctx context.Context, gj *awsv1alpha1.GlueJob,
+	placement *awsv1alpha1.GlueJobPlacement) (*Clients, error) {
+	_, spec, key, err := p.placementSpec(ctx, gj, placement)
+	if err != nil || key == "" {
+		return nil, err
+	}
+	clients, err := p.cached(ctx, key, "", spec)
+	p.mu.Lock()
+	p.forgetUnused(key)
+	p.mu.Unlock()
+	return clients, err
+}
+
+// placementSpec will return the name of AWSProviderConfig of placement, and for placement overriding region
+// or assume role the effective spec and its cache key, which is empty otherwise
+func (p *Provider) placementSpec(ctx context.Context, gj *awsv1alpha1.GlueJob,
+	placement *awsv1alpha1.GlueJobPlacement) (string, *awsv1alpha1.AWSProviderConfigSpec, string, error) {
+	name := ""
+	if placement.ProviderConfigRef != nil {
+		name = placement.ProviderConfigRef.Name
+	} else {
+		var err error
+		name, err = p.ProviderConfigName(ctx, gj)
+		if err != nil {
+			return "", nil, "", err
+		}
+	}
+	if placement.Region == "" && placement.AssumeRole == nil {
+		return name, nil, "", nil
+	}
+
+	spec := p.defaults.DeepCopy()
+	if name != "" {
+		providerConfig, err := p.providerConfig(ctx, name, gj.Namespace)
+		if err != nil {
+			return "", nil, "", err
+		}
+		spec = providerConfig.Spec.DeepCopy()
+	}
+	if placement.Region != "" {
+		spec.Region = placement.Region
+	}
+	if placement.AssumeRole != nil {
+		// otherwise any GlueJob could assume any role trusting the operator
+		if name == "" {
+			return "", nil, "", fmt.Errorf("placement %s assumes role without AWSProviderConfig allowing it",
+				placement.Name)
+		}
+		if !slices.Contains(spec.AllowedAssumeRoles, placement.AssumeRole.RoleARN) {
+			return "", nil, "", fmt.Errorf("role %s of placement %s isn't in allowedAssumeRoles of AWSProviderConfig %s",
+				placement.AssumeRole.RoleARN, placement.Name, name)
+		}
+		spec.AssumeRole = placement.AssumeRole.DeepCopy()
+		spec.WebIdentity = nil
+	}
+	// clients of placements are cached by the effective spec
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", nil, "", err
+	}
+	hash := sha256.Sum256(data)
+	return name, spec, "placement " + hex.EncodeToString(hash[:8]), nil
+}
+
+// ForgetPlacements will remove clients of GlueJob placements from the cache, except of placements in keep,
+// unless other placements use the same clients. It's called for GlueJobs, which are deleted or lose placements
+func (p *Provider) ForgetPlacements(namespace, name string, keep ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prefix := namespace + "/" + name + "/"
+	for owner, key := range p.placementKeys {
+		placement, ok := strings.CutPrefix(owner, prefix)
+		if !ok || slices.Contains(keep, placement) {
+			continue
+		}
+		delete(p.placementKeys, owner)
+		p.forgetUnused(key)
+	}
+}
+
+// usePlacementKey will record, that placement uses clients cached under key, empty key means
+// clients of AWSProviderConfig. Clients of the former key are removed, if no placement uses them
+func (p *Provider) usePlacementKey(owner, key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	former, ok := p.placementKeys[owner]
+	if ok && former == key {
+		return
+	}
+	if key == "" {
+		delete(p.placementKeys, owner)
+	} else {
+		p.placementKeys[owner] = key
+	}
+	if ok {
+		p.forgetUnused(former)
+	}
+}
+
+// forgetUnused will remove placement clients under key from the cache, if no placement uses them.
+// p.mu must be held
+func (p *Provider) forgetUnused(key string) {
+	for _, used := range p.placementKeys {
+		if used == key {
+			return
+		}
+	}
+	delete(p.clients, key)
+}
+
+// cached will return cached clients, if they were created for the same version
+// of AWSProviderConfig, otherwise it creates and caches new clients. Clients are created
+// outside of the lock, concurrent callers of the same key wait for the clients being created
+func (p *Provider) cached(ctx context.Context, key, resourceVersion string,
+	spec *awsv1alpha1.AWSProviderConfigSpec) (*Clients, error) {
+	p.mu.Lock()
+	cached, ok := p.clients[key]
+	if !ok || cached.resourceVersion != resourceVersion {
+		cached = &cachedClients{resourceVersion: resourceVersion, ready: make(chan struct{})}
+		p.clients[key] = cached
+		p.mu.Unlock()
+		cached.clients, cached.err = newClients(ctx, spec)
+		if cached.err != nil {
+			// failed creation is retried by the next caller
+			p.mu.Lock()
+			if p.clients[key] == cached {
+				delete(p.clients, key)
+			}
+			p.mu.Unlock()
+		}
+		close(cached.ready)
+	} else {
+		p.mu.Unlock()
+	}
+
+	select {
+	case <-cached.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if cached.err != nil {
+		if key != "" {
+			return nil, fmt.Errorf("failed to create AWS clients for %s: %w", key, cached.err)
+		}
+		return nil, cached.err
+	}
+	return cached.clients, nil
+}
+
+// forget will remove clients from the cache
+func (p *Provider) forget(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.clients, key)
+}
+
+// newClients will return AWS clients for AWSProviderConfig spec, nil spec means the operator credentials
+func newClients(ctx context.Context, spec *awsv1alpha1.AWSProviderConfigSpec) (*Clients, error) {
+	if spec == nil {
+		spec = &awsv1alpha1.AWSProviderConfigSpec{}
+	}
+	opts := make([]func(*awsconfig.LoadOptions) error, 0)
+	if spec.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(spec.Region))
+	}
+	// Load the Shared AWS Configuration from the Shared config file or IAM Roles
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := spec.Endpoints
+	if endpoints == nil {
+		endpoints = &awsv1alpha1.AWSEndpoints{}
+	}
+	stsOptions := func(o *sts.Options) {
+		if endpoints.STS != "" {
+			o.BaseEndpoint = aws.String(endpoints.STS)
+		}
+	}
+
+	// credentials of the role are assumed with the operator credentials
+	switch {
+	case spec.AssumeRole != nil:
+		awsCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(
+			sts.NewFromConfig(awsCfg, stsOptions), spec.AssumeRole.RoleARN, assumeRoleOptions(spec.AssumeRole)))
+	case spec.WebIdentity != nil:
+		awsCfg.Credentials = aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(
+			sts.NewFromConfig(awsCfg, stsOptions), spec.WebIdentity.RoleARN,
+			stscreds.IdentityTokenFile(spec.WebIdentity.TokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = sessionName(spec.WebIdentity.SessionName)
+			}))
+	}
+
+	// Get Account ID
+	result, err := sts.NewFromConfig(awsCfg, stsOptions).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GetCallerIdentity: %w", err)
+	}
+
+	// Create an Amazon Glue service client
+	glueClient := awsglue.NewFromConfig(awsCfg, func(o *awsglue.Options) {
+		if endpoints.Glue != "" {
+			o.BaseEndpoint = aws.String(endpoints.Glue)
+		}
+	})
+	s3Client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if endpoints.S3 != "" {
+			o.BaseEndpoint = aws.String(endpoints.S3)
+			o.UsePathStyle = true
+		}
+	})
+	secretsClient := secretsmanager.NewFromConfig(awsCfg, func(o *secretsmanager.Options) {
+		if endpoints.SecretsManager != "" {
+			o.BaseEndpoint = aws.String(endpoints.SecretsManager)
+		}
+	})
+	ssmClient := ssm.NewFromConfig(awsCfg, func(o *ssm.Options) {
+		if endpoints.SSM != "" {
+			o.BaseEndpoint = aws.String(endpoints.SSM)
+		}
+	})
+	iamClient := iam.NewFromConfig(awsCfg, func(o *iam.Options) {
+		if endpoints.IAM != "" {
+			o.BaseEndpoint = aws.String(endpoints.IAM)
+		}
+	})
+	return &Clients{
+		Config:         awsCfg,
+		Glue:           glueClient,
+		S3:             s3Client,
+		SecretsManager: secretsClient,
+		SSM:            ssmClient,
+		IAM:            iamClient,
+		AccountID:      aws.ToString(result.Account),
+		Region:         awsCfg.Region,
+		ScriptBucket:   spec.ScriptBucket,
+	}, nil
+}
+
+// assumeRoleOptions will return function setting options of AssumeRole call
+func assumeRoleOptions(role *awsv1alpha1.AWSAssumeRole) func(*stscreds.AssumeRoleOptions) {
+	return func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName(role.SessionName)
+		if role.ExternalID != "" {
+			o.ExternalID = aws.String(role.ExternalID)
+		}
+		if role.Duration != nil {
+			o.Duration = role.Duration.Duration
+		}
+		keys := make([]string, 0, len(role.SessionTags))
+		for key := range role.SessionTags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			o.Tags = append(o.Tags, ststypes.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(role.SessionTags[key]),
+			})
+		}
+	}
+}
+
+// sessionName will return role session name or the default one
+func sessionName(name string) string {
+	if name == "" {
+		return defaultSessionName
+	}
+	return name
+}
@@ -0,0 +1,183 @@
+package awsclient
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+const testRoleARN = "arn:aws:iam::123456789012:role/dr"
+
+// newTestProvider will return Provider reading AWSProviderConfig restricted to namespace team-a
+func newTestProvider(t *testing.T) *Provider {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := awsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	providerConfig := &awsv1alpha1.AWSProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+		Spec: awsv1alpha1.AWSProviderConfigSpec{
+			AllowedNamespaces:  []string{"team-a"},
+			AllowedAssumeRoles: []string{testRoleARN},
+		},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(providerConfig).Build()
+	return NewProvider(reader, reader, config.OperatorConfig{})
+}
+
+func TestProviderConfigAllowedNamespaces(t *testing.T) {
+	provider := newTestProvider(t)
+	ctx := context.Background()
+	for namespace, allowed := range map[string]bool{"team-a": true, "team-b": false, "": true} {
+		_, err := provider.providerConfig(ctx, "team-a", namespace)
+		if (err == nil) != allowed {
+			t.Errorf("expected namespace %q allowed %v, got %v", namespace, allowed, err)
+		}
+	}
+	if _, err := provider.ForProviderConfig(ctx, "team-a", "team-b"); err == nil {
+		t.Error("expected error of namespace not allowed")
+	}
+}
+
+func TestForPlacementAllowedAssumeRoles(t *testing.T) {
+	provider := newTestProvider(t)
+	tests := []struct {
+		name      string
+		namespace string
+		placement awsv1alpha1.GlueJobPlacement
+	}{
+		{
+			name:      "role without AWSProviderConfig",
+			namespace: "team-a",
+			placement: awsv1alpha1.GlueJobPlacement{
+				Name:       "dr",
+				AssumeRole: &awsv1alpha1.AWSAssumeRole{RoleARN: testRoleARN},
+			},
+		},
+		{
+			name:      "role not allowed by AWSProviderConfig",
+			namespace: "team-a",
+			placement: awsv1alpha1.GlueJobPlacement{
+				Name:              "dr",
+				ProviderConfigRef: &awsv1alpha1.ProviderConfigReference{Name: "team-a"},
+				AssumeRole:        &awsv1alpha1.AWSAssumeRole{RoleARN: "arn:aws:iam::123456789012:role/admin"},
+			},
+		},
+		{
+			name:      "AWSProviderConfig not allowed in namespace",
+			namespace: "team-b",
+			placement: awsv1alpha1.GlueJobPlacement{
+				Name:              "dr",
+				ProviderConfigRef: &awsv1alpha1.ProviderConfigReference{Name: "team-a"},
+				AssumeRole:        &awsv1alpha1.AWSAssumeRole{RoleARN: testRoleARN},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gj := &awsv1alpha1.GlueJob{
+				ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace, Name: "etl"},
+				Spec:       awsv1alpha1.GlueJobSpec{ProviderConfigRef: tt.placement.ProviderConfigRef},
+			}
+			if _, err := provider.ForPlacement(context.Background(), gj, &tt.placement); err == nil {
+				t.Error("expected error of placement not allowed")
+			}
+		})
+	}
+}
+
+func TestForgetPlacements(t *testing.T) {
+	provider := newTestProvider(t)
+	for _, key := range []string{"placement a", "placement b", "AWSProviderConfig team-a"} {
+		provider.clients[key] = &cachedClients{ready: make(chan struct{})}
+	}
+	provider.usePlacementKey("team-a/etl/primary", "placement a")
+	provider.usePlacementKey("team-a/etl/dr", "placement b")
+	provider.usePlacementKey("team-a/other/dr", "placement b")
+
+	// placement moved to AWSProviderConfig clients
+	provider.usePlacementKey("team-a/etl/primary", "")
+	if _, ok := provider.clients["placement a"]; ok {
+		t.Error("expected unused clients to be removed")
+	}
+	provider.ForgetPlacements("team-a", "etl")
+	if _, ok := provider.clients["placement b"]; !ok {
+		t.Error("expected clients used by another GlueJob to be kept")
+	}
+	provider.ForgetPlacements("team-a", "other", "dr")
+	if _, ok := provider.clients["placement b"]; !ok {
+		t.Error("expected clients of kept placement to be kept")
+	}
+	provider.ForgetPlacements("team-a", "other")
+	if _, ok := provider.clients["placement b"]; ok {
+		t.Error("expected clients of removed placements to be removed")
+	}
+	if _, ok := provider.clients["AWSProviderConfig team-a"]; !ok {
+		t.Error("expected clients of AWSProviderConfig to be kept")
+	}
+}
+
+func TestCachedWaitsForClients(t *testing.T) {
+	provider := newTestProvider(t)
+	creating := &cachedClients{resourceVersion: "1", ready: make(chan struct{})}
+	provider.clients["AWSProviderConfig team-a"] = creating
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := provider.cached(ctx, "AWSProviderConfig team-a", "1", nil); err != context.Canceled {
+		t.Errorf("expected to wait for clients being created, got %v", err)
+	}
+	creating.clients = &Clients{AccountID: "123456789012"}
+	close(creating.ready)
+	clients, err := provider.cached(context.Background(), "AWSProviderConfig team-a", "1", nil)
+	if err != nil || clients.AccountID != "123456789012" {
+		t.Errorf("expected created clients, got %v: %v", clients, err)
+	}
+}
+
+func TestForPlacementOverride(t *testing.T) {
+	provider := newTestProvider(t)
+	ctx := context.Background()
+	gj := &awsv1alpha1.GlueJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "etl"},
+		Spec: awsv1alpha1.GlueJobSpec{
+			ProviderConfigRef: &awsv1alpha1.ProviderConfigReference{Name: "team-a"},
+		},
+	}
+	clients, err := provider.ForPlacementOverride(ctx, gj, &awsv1alpha1.GlueJobPlacement{Name: "primary"})
+	if err != nil || clients != nil {
+		t.Errorf("expected no clients of placement without override, got %v: %v", clients, err)
+	}
+
+	placement := &awsv1alpha1.GlueJobPlacement{Name: "dr", Region: "eu-central-1"}
+	_, _, key, err := provider.placementSpec(ctx, gj, placement)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ready := &cachedClients{ready: make(chan struct{}), clients: &Clients{Region: "eu-central-1"}}
+	close(ready.ready)
+	provider.clients[key] = ready
+	clients, err = provider.ForPlacementOverride(ctx, gj, placement)
+	if err != nil || clients.Region != "eu-central-1" {
+		t.Errorf("expected clients of placement region, got %v: %v", clients, err)
+	}
+	if _, ok := provider.clients[key]; ok {
+		t.Error("expected clients no placement uses to be removed")
+	}
+
+	provider.clients[key] = ready
+	provider.usePlacementKey("team-a/etl/dr", key)
+	if _, err = provider.ForPlacementOverride(ctx, gj, placement); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := provider.clients[key]; !ok {
+		t.Error("expected clients used by placement to be kept")
+	}
+}
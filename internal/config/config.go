@@ -1,26 +1,336 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// APIVersion is the version of the config file format
+	APIVersion = "glue-jobs-operator.90poe.io/v1alpha1"
+	// Kind is the kind of the config file
+	Kind = "OperatorConfig"
+	// FileEnv is the env variable with path of the config file
+	FileEnv = "CONFIG_FILE"
+	// LeaderElectionID is the leader election ID of the operator without sharding
+	LeaderElectionID = "a4b9d8a1.90poe.io"
+)
+
+const (
+	// FeatureValidatingWebhook enables validating webhook for GlueJobs, same as EnableWebhooks
+	FeatureValidatingWebhook = "ValidatingWebhook"
+	// FeatureOrphanCollector enables garbage collector of orphaned Glue Jobs
+	FeatureOrphanCollector = "OrphanCollector"
+)
+
+// featureDefaults are known feature gates with their default values
+var featureDefaults = map[string]bool{
+	FeatureValidatingWebhook: false,
+	FeatureOrphanCollector:   true,
+}
+
+// placeholderRe matches placeholders of the Glue Job name template
+var placeholderRe = regexp.MustCompile(`{{[^}]*}}`)
+
 type (
-	// Configuration from Env
+	// Configuration from the config file and Env, Env overrides the config file
 	OperatorConfig struct {
+		// APIVersion is the version of the config file format
+		APIVersion string `yaml:"apiVersion"`
+		// Kind is the kind of the config file
+		Kind string `yaml:"kind"`
 		// NOTE: Log Level is set via zap-log-level flag passed to the operator
 		// MaxConcurrentReconciles is the maximum number of concurrent Reconciles which can be run.
-		MaxConcurrentReconciles int `env:"MAX_CONCURRENT_RECONCILES" env-default:"1"`
+		MaxConcurrentReconciles int `yaml:"maxConcurrentReconciles" env:"MAX_CONCURRENT_RECONCILES" env-default:"1"`
+		// RateLimiterBaseDelay is the base delay of per GlueJob exponential backoff of failed reconciles
+		RateLimiterBaseDelay time.Duration `yaml:"rateLimiterBaseDelay" env:"RATE_LIMITER_BASE_DELAY" env-default:"5ms"`
+		// RateLimiterMaxDelay is the max delay of per GlueJob exponential backoff of failed reconciles
+		RateLimiterMaxDelay time.Duration `yaml:"rateLimiterMaxDelay" env:"RATE_LIMITER_MAX_DELAY" env-default:"1000s"`
+		// RateLimiterQPS is the overall rate of reconciles per second
+		RateLimiterQPS float64 `yaml:"rateLimiterQPS" env:"RATE_LIMITER_QPS" env-default:"10"`
+		// RateLimiterBurst is the overall burst of reconciles
+		RateLimiterBurst int `yaml:"rateLimiterBurst" env:"RATE_LIMITER_BURST" env-default:"100"`
+		// WatchNamespaces is the list of namespaces, which GlueJobs are watched in, empty means all namespaces
+		WatchNamespaces []string `yaml:"watchNamespaces" env:"WATCH_NAMESPACES" env-separator:","`
+		// WatchNamespaceSelector is the label selector of namespaces, which GlueJobs are watched in,
+		// e.g. "team=a". It's exclusive with WatchNamespaces
+		WatchNamespaceSelector string `yaml:"watchNamespaceSelector" env:"WATCH_NAMESPACE_SELECTOR"`
+		// ShardName is the name of the shard of this instance, GlueJobs are split between instances by shard
+		// selector or by hash of GlueJob namespace and name. Empty means no sharding
+		ShardName string `yaml:"shardName" env:"SHARD_NAME"`
+		// ShardSelector is the label selector of GlueJobs in the shard, e.g. "shard=a"
+		ShardSelector string `yaml:"shardSelector" env:"SHARD_SELECTOR"`
+		// ShardCount is the number of shards GlueJobs are split into by hash of namespace and name
+		ShardCount int `yaml:"shardCount" env:"SHARD_COUNT"`
+		// ShardIndex is the index of the shard from 0 to ShardCount-1
+		ShardIndex int `yaml:"shardIndex" env:"SHARD_INDEX"`
+		// IgnoredTagPrefixes is the list of tag key prefixes, which are managed by other tooling
+		// (e.g. AWS Backup, cost allocation) and must not be removed from Glue Jobs by the operator.
+		IgnoredTagPrefixes []string `yaml:"ignoredTagPrefixes" env:"IGNORED_TAG_PREFIXES" env-separator:","`
+		// ClusterID is the ID of the cluster, which is tagged on owned Glue Jobs, so multiple
+		// clusters sharing an AWS account don't fight over the same Glue Jobs. It's required.
+		ClusterID string `yaml:"clusterID" env:"CLUSTER_ID"`
+		// JobNameTemplate is the template of Glue Job names for GlueJobs without spec.name,
+		// supported placeholders are {{cluster}}, {{namespace}} and {{name}}
+		JobNameTemplate string `yaml:"jobNameTemplate" env:"JOB_NAME_TEMPLATE" env-default:"{{cluster}}_{{namespace}}_{{name}}"`
+		// NamespacePrefixes is the map of namespace to the prefix, which Glue Job names
+		// of GlueJobs in the namespace must start with, e.g. "team-a:team-a-,team-b:tb-"
+		NamespacePrefixes map[string]string `yaml:"namespacePrefixes" env:"NAMESPACE_NAME_PREFIXES"`
+		// AWSRegion is the region of Glue Jobs without AWSProviderConfig, empty means the default AWS config
+		AWSRegion string `yaml:"awsRegion" env:"AWS_REGION"`
+		// AWSAssumeRoleARN is the role assumed for Glue Jobs without AWSProviderConfig
+		AWSAssumeRoleARN string `yaml:"awsAssumeRoleARN" env:"AWS_ASSUME_ROLE_ARN"`
+		// AWSAssumeRoleExternalID is the external ID passed, when AWSAssumeRoleARN is assumed
+		AWSAssumeRoleExternalID string `yaml:"awsAssumeRoleExternalID" env:"AWS_ASSUME_ROLE_EXTERNAL_ID"`
+		// ScriptBucket is the S3 bucket, which scripts from script sources are uploaded to,
+		// AWSProviderConfig may override it
+		ScriptBucket string `yaml:"scriptBucket" env:"SCRIPT_BUCKET"`
+		// SourceBuckets are the S3 buckets besides the script bucket, which staged Python modules may be read from
+		// with the operator credentials
+		SourceBuckets []string `yaml:"sourceBuckets" env:"SOURCE_BUCKETS" env-separator:","`
+		// ScriptPrefix is the key prefix of uploaded scripts
+		ScriptPrefix string `yaml:"scriptPrefix" env:"SCRIPT_PREFIX" env-default:"glue-jobs-operator/scripts"`
+		// EnableWebhooks enables validating webhook for GlueJobs, it requires webhook serving certificates
+		EnableWebhooks bool `yaml:"enableWebhooks" env:"ENABLE_WEBHOOKS" env-default:"false"`
+		// FeatureGates is the map of feature gate to its state, e.g. "OrphanCollector:false"
+		FeatureGates map[string]bool `yaml:"featureGates" env:"FEATURE_GATES"`
+		// OrphanGCInterval is how often owned Glue Jobs without GlueJob are looked for, 0 disables it
+		OrphanGCInterval time.Duration `yaml:"orphanGCInterval" env:"ORPHAN_GC_INTERVAL" env-default:"1h"`
+		// OrphanGCGracePeriod is how long Glue Job must stay orphaned before it's deleted
+		OrphanGCGracePeriod time.Duration `yaml:"orphanGCGracePeriod" env:"ORPHAN_GC_GRACE_PERIOD" env-default:"24h"`
+		// OrphanGCDelete enables deletion of orphaned Glue Jobs, otherwise they are only reported
+		OrphanGCDelete bool `yaml:"orphanGCDelete" env:"ORPHAN_GC_DELETE" env-default:"false"`
+		// OrphanGCDryRun makes garbage collector only log orphaned Glue Jobs it would delete
+		OrphanGCDryRun bool `yaml:"orphanGCDryRun" env:"ORPHAN_GC_DRY_RUN" env-default:"false"`
+		// OrphanGCShard is the name of the only shard, which collects orphaned Glue Jobs, when GlueJobs are
+		// split by shardSelector. Labels of deleted GlueJobs are unknown, so their shard can't be told
+		OrphanGCShard string `yaml:"orphanGCShard" env:"ORPHAN_GC_SHARD"`
+		// ResyncPeriod is how often GlueJobs are reconciled without changes to recover from drift, 0 disables it
+		ResyncPeriod time.Duration `yaml:"resyncPeriod" env:"RESYNC_PERIOD" env-default:"10m"`
+		// ResyncJitter is the max fraction of resync period added to it to spread reconciles over time
+		ResyncJitter float64 `yaml:"resyncJitter" env:"RESYNC_JITTER" env-default:"0.1"`
+		// ObserveOnly makes the operator only look up and diff Glue Jobs, overriding managementPolicy
+		// of all GlueJobs. Planned changes are reported, but never made. Orphaned Glue Jobs are never deleted
+		ObserveOnly bool `yaml:"observeOnly" env:"OBSERVE_ONLY" env-default:"false"`
 	}
 )
 
+// New will return the operator config read from the config file set in CONFIG_FILE env and from Env
 func New() (OperatorConfig, error) {
-	// get config from Env
+	return Load(os.Getenv(FileEnv))
+}
+
+// Load will return the operator config read from the config file and from Env, empty path means Env only
+func Load(path string) (OperatorConfig, error) {
 	cfg := OperatorConfig{}
-	err := cleanenv.ReadEnv(&cfg)
+	var err error
+	if path != "" {
+		err = cleanenv.ReadConfig(path, &cfg)
+	} else {
+		// get config from Env
+		err = cleanenv.ReadEnv(&cfg)
+	}
 	if err != nil {
 		return cfg, fmt.Errorf("can't make operator config: %w", err)
 	}
+	if path != "" && (cfg.APIVersion != APIVersion || cfg.Kind != Kind) {
+		return cfg, fmt.Errorf("config file %s must have apiVersion %s and kind %s", path, APIVersion, Kind)
+	}
+	err = cfg.Validate()
+	if err != nil {
+		return cfg, fmt.Errorf("invalid operator config: %w", err)
+	}
 	return cfg, nil
 }
+
+// Validate will return error, if the config has invalid values
+func (c OperatorConfig) Validate() error {
+	if c.ClusterID == "" {
+		return fmt.Errorf("clusterID is required, set a unique ID per cluster")
+	}
+	if errs := validation.IsDNS1123Label(c.ClusterID); len(errs) > 0 {
+		return fmt.Errorf("invalid clusterID: %s", strings.Join(errs, ", "))
+	}
+	if c.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("maxConcurrentReconciles must be at least 1")
+	}
+	if c.RateLimiterBaseDelay < 0 || c.RateLimiterMaxDelay < c.RateLimiterBaseDelay {
+		return fmt.Errorf("rateLimiterMaxDelay must not be less than rateLimiterBaseDelay, which must not be negative")
+	}
+	if c.RateLimiterQPS <= 0 || c.RateLimiterBurst <= 0 {
+		return fmt.Errorf("rateLimiterQPS and rateLimiterBurst must be positive")
+	}
+	if c.ResyncPeriod < 0 || c.OrphanGCInterval < 0 || c.OrphanGCGracePeriod < 0 {
+		return fmt.Errorf("resyncPeriod, orphanGCInterval and orphanGCGracePeriod must not be negative")
+	}
+	if len(c.WatchNamespaces) > 0 && c.WatchNamespaceSelector != "" {
+		return fmt.Errorf("watchNamespaces and watchNamespaceSelector are mutually exclusive")
+	}
+	if _, err := c.NamespaceSelector(); err != nil {
+		return err
+	}
+	if err := c.validateShard(); err != nil {
+		return err
+	}
+	if c.ResyncJitter < 0 || c.ResyncJitter > 1 {
+		return fmt.Errorf("resyncJitter must be between 0 and 1")
+	}
+	for _, placeholder := range placeholderRe.FindAllString(c.JobNameTemplate, -1) {
+		switch placeholder {
+		case "{{cluster}}", "{{namespace}}", "{{name}}":
+		default:
+			return fmt.Errorf("jobNameTemplate has unknown placeholder %s", placeholder)
+		}
+	}
+	for gate := range c.FeatureGates {
+		if _, ok := featureDefaults[gate]; !ok {
+			return fmt.Errorf("unknown feature gate %s", gate)
+		}
+	}
+	return nil
+}
+
+// validateShard will return error, if the shard settings are invalid
+func (c OperatorConfig) validateShard() error {
+	if c.ShardName == "" {
+		if c.ShardSelector != "" || c.ShardCount != 0 || c.OrphanGCShard != "" {
+			return fmt.Errorf("shardSelector, shardCount and orphanGCShard require shardName")
+		}
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(c.ShardName); len(errs) > 0 {
+		return fmt.Errorf("invalid shardName: %s", strings.Join(errs, ", "))
+	}
+	if (c.ShardSelector == "") == (c.ShardCount == 0) {
+		return fmt.Errorf("shardName requires either shardSelector or shardCount")
+	}
+	if c.ShardSelector != "" {
+		if _, err := labels.Parse(c.ShardSelector); err != nil {
+			return fmt.Errorf("invalid shardSelector: %w", err)
+		}
+		if c.OrphanGCShard != "" {
+			if errs := validation.IsDNS1123Label(c.OrphanGCShard); len(errs) > 0 {
+				return fmt.Errorf("invalid orphanGCShard: %s", strings.Join(errs, ", "))
+			}
+		}
+		return nil
+	}
+	if c.OrphanGCShard != "" {
+		return fmt.Errorf("orphanGCShard requires shardSelector")
+	}
+	if c.ShardCount < 0 || c.ShardIndex < 0 || c.ShardIndex >= c.ShardCount {
+		return fmt.Errorf("shardIndex must be from 0 to shardCount-1")
+	}
+	return nil
+}
+
+// CollectsOrphans will return true, if this instance runs the orphaned Glue Jobs garbage collector.
+// Shards split by hash collect orphaned Glue Jobs of their GlueJobs, shards split by selector
+// leave all orphaned Glue Jobs to orphanGCShard
+func (c OperatorConfig) CollectsOrphans() bool {
+	if c.OrphanGCInterval <= 0 || !c.FeatureEnabled(FeatureOrphanCollector) {
+		return false
+	}
+	return c.ShardSelector == "" || c.ShardName == c.OrphanGCShard
+}
+
+// Sharded will return true, if GlueJobs are split between several instances of the operator
+func (c OperatorConfig) Sharded() bool {
+	return c.ShardName != ""
+}
+
+// InShard will return true, if GlueJob with namespace, name and labels is in the shard of this instance
+func (c OperatorConfig) InShard(namespace, name string, glueJobLabels map[string]string) bool {
+	switch {
+	case c.ShardSelector != "":
+		selector, err := labels.Parse(c.ShardSelector)
+		if err != nil {
+			return false
+		}
+		return selector.Matches(labels.Set(glueJobLabels))
+	case c.ShardCount > 0:
+		return ShardIndex(namespace, name, c.ShardCount) == c.ShardIndex
+	}
+	return true
+}
+
+// ShardIndex will return index of the shard of GlueJob by hash of its namespace and name
+func ShardIndex(namespace, name string, count int) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(namespace + "/" + name))
+	return int(hash.Sum32() % uint32(count))
+}
+
+// ShardLeaderElectionID will return the leader election ID of shard, empty shard means no sharding
+func ShardLeaderElectionID(shard string) string {
+	if shard == "" {
+		return LeaderElectionID
+	}
+	return shard + "-" + LeaderElectionID
+}
+
+// NamespaceSelector will return the label selector of watched namespaces, nil means no selector
+func (c OperatorConfig) NamespaceSelector() (labels.Selector, error) {
+	if c.WatchNamespaceSelector == "" {
+		return nil, nil
+	}
+	selector, err := labels.Parse(c.WatchNamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid watchNamespaceSelector: %w", err)
+	}
+	return selector, nil
+}
+
+// Watches will return true, if GlueJobs in namespace with labels are watched
+func (c OperatorConfig) Watches(namespace string, namespaceLabels map[string]string) bool {
+	if len(c.WatchNamespaces) > 0 && !slices.Contains(c.WatchNamespaces, namespace) {
+		return false
+	}
+	selector, err := c.NamespaceSelector()
+	if err != nil {
+		return false
+	}
+	if selector == nil {
+		return true
+	}
+	return selector.Matches(labels.Set(namespaceLabels))
+}
+
+// WatchesNamespace will return true, if GlueJobs in namespace are watched, namespace is read with reader
+// only when namespace label selector is set
+func (c OperatorConfig) WatchesNamespace(ctx context.Context, reader client.Reader, namespace string) (bool, error) {
+	if c.WatchNamespaceSelector == "" {
+		return c.Watches(namespace, nil), nil
+	}
+	ns := &corev1.Namespace{}
+	err := reader.Get(ctx, client.ObjectKey{Name: namespace}, ns)
+	if err != nil {
+		return false, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	return c.Watches(namespace, ns.Labels), nil
+}
+
+// FeatureEnabled will return true, if the feature gate is enabled
+func (c OperatorConfig) FeatureEnabled(gate string) bool {
+	if enabled, ok := c.FeatureGates[gate]; ok {
+		return enabled
+	}
+	return featureDefaults[gate]
+}
+
+// WebhooksEnabled will return true, if validating webhook is enabled either by EnableWebhooks or feature gate
+func (c OperatorConfig) WebhooksEnabled() bool {
+	return c.EnableWebhooks || c.FeatureEnabled(FeatureValidatingWebhook)
+}
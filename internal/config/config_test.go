@@ -0,0 +1,234 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// validConfig will return config with defaults, which passes validation
+func validConfig() OperatorConfig {
+	return OperatorConfig{
+		ClusterID:               "prod",
+		MaxConcurrentReconciles: 1,
+		RateLimiterBaseDelay:    5 * time.Millisecond,
+		RateLimiterMaxDelay:     1000 * time.Second,
+		RateLimiterQPS:          10,
+		RateLimiterBurst:        100,
+		JobNameTemplate:         "{{cluster}}_{{namespace}}_{{name}}",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*OperatorConfig)
+		valid  bool
+	}{
+		{name: "defaults", modify: func(*OperatorConfig) {}, valid: true},
+		{name: "missing clusterID", modify: func(c *OperatorConfig) { c.ClusterID = "" }},
+		{name: "invalid clusterID", modify: func(c *OperatorConfig) { c.ClusterID = "Prod_EU" }},
+		{name: "no concurrent reconciles", modify: func(c *OperatorConfig) { c.MaxConcurrentReconciles = 0 }},
+		{name: "max delay below base delay", modify: func(c *OperatorConfig) { c.RateLimiterMaxDelay = time.Millisecond }},
+		{name: "zero QPS", modify: func(c *OperatorConfig) { c.RateLimiterQPS = 0 }},
+		{name: "negative resync period", modify: func(c *OperatorConfig) { c.ResyncPeriod = -time.Minute }},
+		{name: "disabled resync", modify: func(c *OperatorConfig) { c.ResyncPeriod = 0 }, valid: true},
+		{name: "jitter above 1", modify: func(c *OperatorConfig) { c.ResyncJitter = 1.5 }},
+		{name: "negative orphan GC grace period", modify: func(c *OperatorConfig) { c.OrphanGCGracePeriod = -time.Hour }},
+		{
+			name: "watched namespaces and selector",
+			modify: func(c *OperatorConfig) {
+				c.WatchNamespaces = []string{"team-a"}
+				c.WatchNamespaceSelector = "team=a"
+			},
+		},
+		{name: "invalid namespace selector", modify: func(c *OperatorConfig) { c.WatchNamespaceSelector = "team=a=b" }},
+		{name: "shard without name", modify: func(c *OperatorConfig) { c.ShardCount = 2 }},
+		{name: "shard name without split", modify: func(c *OperatorConfig) { c.ShardName = "s" }},
+		{
+			name: "shard selector and count",
+			modify: func(c *OperatorConfig) {
+				c.ShardName, c.ShardSelector, c.ShardCount = "s", "shard=s", 2
+			},
+		},
+		{
+			name:   "shard selector",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardSelector = "s", "shard=s" },
+			valid:  true,
+		},
+		{
+			name:   "shard index out of range",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardCount, c.ShardIndex = "s", 2, 2 },
+		},
+		{
+			name:   "shard index",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardCount, c.ShardIndex = "s", 2, 1 },
+			valid:  true,
+		},
+		{
+			name: "orphan GC shard",
+			modify: func(c *OperatorConfig) {
+				c.ShardName, c.ShardSelector, c.OrphanGCShard = "a", "team=a", "b"
+			},
+			valid: true,
+		},
+		{
+			name: "invalid orphan GC shard",
+			modify: func(c *OperatorConfig) {
+				c.ShardName, c.ShardSelector, c.OrphanGCShard = "a", "team=a", "Team_B"
+			},
+		},
+		{
+			name:   "orphan GC shard with hash shards",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardCount, c.OrphanGCShard = "s", 2, "s" },
+		},
+		{name: "unknown placeholder", modify: func(c *OperatorConfig) { c.JobNameTemplate = "{{cluster}}_{{uid}}" }},
+		{
+			name:   "unknown feature gate",
+			modify: func(c *OperatorConfig) { c.FeatureGates = map[string]bool{"Unknown": true} },
+		},
+		{
+			name:   "known feature gate",
+			modify: func(c *OperatorConfig) { c.FeatureGates = map[string]bool{FeatureOrphanCollector: false} },
+			valid:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+			if err := cfg.Validate(); (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestInShard(t *testing.T) {
+	index := ShardIndex("team-a", "job", 2)
+	tests := []struct {
+		name   string
+		modify func(*OperatorConfig)
+		labels map[string]string
+		want   bool
+	}{
+		{name: "no sharding", modify: func(*OperatorConfig) {}, want: true},
+		{
+			name:   "selector matches",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardSelector = "a", "team=a" },
+			labels: map[string]string{"team": "a"},
+			want:   true,
+		},
+		{
+			name:   "selector doesn't match",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardSelector = "a", "team=a" },
+			labels: map[string]string{"team": "b"},
+		},
+		{
+			name:   "selector without labels",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardSelector = "a", "team=a" },
+		},
+		{
+			name:   "hash of own shard",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardCount, c.ShardIndex = "s", 2, index },
+			want:   true,
+		},
+		{
+			name:   "hash of other shard",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardCount, c.ShardIndex = "s", 2, 1-index },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+			if got := cfg.InShard("team-a", "job", tt.labels); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCollectsOrphans(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*OperatorConfig)
+		want   bool
+	}{
+		{name: "no sharding", modify: func(*OperatorConfig) {}, want: true},
+		{name: "disabled interval", modify: func(c *OperatorConfig) { c.OrphanGCInterval = 0 }},
+		{
+			name:   "disabled feature gate",
+			modify: func(c *OperatorConfig) { c.FeatureGates = map[string]bool{FeatureOrphanCollector: false} },
+		},
+		{
+			name:   "hash shard",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardCount, c.ShardIndex = "s", 2, 1 },
+			want:   true,
+		},
+		{
+			name:   "selector shard without orphan GC shard",
+			modify: func(c *OperatorConfig) { c.ShardName, c.ShardSelector = "a", "team=a" },
+		},
+		{
+			name: "orphan GC shard",
+			modify: func(c *OperatorConfig) {
+				c.ShardName, c.ShardSelector, c.OrphanGCShard = "a", "team=a", "a"
+			},
+			want: true,
+		},
+		{
+			name: "other orphan GC shard",
+			modify: func(c *OperatorConfig) {
+				c.ShardName, c.ShardSelector, c.OrphanGCShard = "a", "team=a", "b"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.OrphanGCInterval = time.Hour
+			tt.modify(&cfg)
+			if got := cfg.CollectsOrphans(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStoreApply(t *testing.T) {
+	current := validConfig()
+	store := NewStore(current, "config.yaml", logr.Discard())
+
+	next := validConfig()
+	// reloaded settings
+	next.IgnoredTagPrefixes = []string{"backup-"}
+	next.ResyncPeriod = time.Hour
+	next.ResyncJitter = 0.5
+	next.OrphanGCGracePeriod = time.Minute
+	next.OrphanGCDelete = true
+	next.OrphanGCDryRun = true
+	next.ObserveOnly = true
+	// settings requiring restart
+	next.ClusterID = "dev"
+	next.JobNameTemplate = "{{name}}"
+	next.MaxConcurrentReconciles = 8
+	next.WatchNamespaces = []string{"team-a"}
+	next.ShardName = "s"
+	next.OrphanGCInterval = time.Minute
+	store.apply(next)
+
+	expected := current
+	expected.IgnoredTagPrefixes = next.IgnoredTagPrefixes
+	expected.ResyncPeriod = next.ResyncPeriod
+	expected.ResyncJitter = next.ResyncJitter
+	expected.OrphanGCGracePeriod = next.OrphanGCGracePeriod
+	expected.OrphanGCDelete = next.OrphanGCDelete
+	expected.OrphanGCDryRun = next.OrphanGCDryRun
+	expected.ObserveOnly = next.ObserveOnly
+	if applied := store.Get(); !reflect.DeepEqual(applied, expected) {
+		t.Errorf("expected config %+v, got %+v", expected, applied)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+)
+
+// Store holds the operator config and reloads it, when the config file changes.
+// Only settings, which are safe to change at runtime, are applied on reload,
+// the rest require restart of the operator.
+type Store struct {
+	mu     sync.RWMutex
+	config OperatorConfig
+	path   string
+	log    logr.Logger
+}
+
+// NewStore will return a new Store with the config loaded from path, empty path means Env only
+func NewStore(cfg OperatorConfig, path string, log logr.Logger) *Store {
+	return &Store{
+		config: cfg,
+		path:   path,
+		log:    log,
+	}
+}
+
+// StaticStore will return a Store with fixed config, which is never reloaded
+func StaticStore(cfg OperatorConfig) *Store {
+	return &Store{config: cfg}
+}
+
+// Get will return the current config
+func (s *Store) Get() OperatorConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// NeedLeaderElection makes the config reloaded on every replica
+func (s *Store) NeedLeaderElection() bool {
+	return false
+}
+
+// Start will watch the config file and reload the config on changes until context is done
+func (s *Store) Start(ctx context.Context) error {
+	if s.path == "" {
+		return nil
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch config file: %w", err)
+	}
+	defer watcher.Close()
+	// ConfigMap volumes replace files by swapping symlinks, so the directory is watched
+	err = watcher.Add(filepath.Dir(s.path))
+	if err != nil {
+		return fmt.Errorf("failed to watch config file %s: %w", s.path, err)
+	}
+	s.log.V(0).Info("Watching config file for changes", "path", s.path)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			s.reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			s.log.V(0).Error(err, "Failed to watch config file", "path", s.path)
+		}
+	}
+}
+
+// reload will load the config file and apply it, invalid config is ignored
+func (s *Store) reload() {
+	cfg, err := Load(s.path)
+	if err != nil {
+		s.log.V(0).Error(err, "Failed to reload config file, keeping current config", "path", s.path)
+		return
+	}
+	s.apply(cfg)
+}
+
+// apply will apply settings of next config, which are safe to change at runtime
+func (s *Store) apply(next OperatorConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.config
+	applied := current
+	applied.IgnoredTagPrefixes = next.IgnoredTagPrefixes
+	applied.ResyncPeriod = next.ResyncPeriod
+	applied.ResyncJitter = next.ResyncJitter
+	applied.OrphanGCGracePeriod = next.OrphanGCGracePeriod
+	applied.OrphanGCDelete = next.OrphanGCDelete
+	applied.OrphanGCDryRun = next.OrphanGCDryRun
+	applied.ObserveOnly = next.ObserveOnly
+	if !reflect.DeepEqual(applied, current) {
+		s.log.V(0).Info("Reloaded config file", "path", s.path)
+	}
+	if !reflect.DeepEqual(applied, next) {
+		s.log.V(0).Info("Config file has changes, which require restart of the operator", "path", s.path)
+	}
+	s.config = applied
+}
@@ -7,6 +7,60 @@ const (
 	UnrecoverableError = "UnrecoverableError"
 	SuccessReconcile   = "Success"
 	// GlueJob status Type
-	StatusReady    = "Ready"
+	StatusReady = "Ready"
+	// StatusNotReady was appended by older versions of the operator, Ready condition is false instead
 	StatusNotReady = "NotReady"
+	// StatusOwnershipConflict is set when Glue Job on AWS is owned by another cluster or GlueJob
+	StatusOwnershipConflict = "OwnershipConflict"
+	// StatusConflict is set when another GlueJob uses the same Glue Job name
+	StatusConflict = "Conflict"
+	// StatusPaused is set when reconciliation of GlueJob is paused by annotation
+	StatusPaused = "Paused"
+	// Reasons for Paused condition
+	ReasonPaused  = "PausedByAnnotation"
+	ReasonResumed = "Resumed"
+	// StatusSynced is set when Glue Job on AWS matches the spec
+	StatusSynced = "Synced"
+	// ReasonMissing is set when Glue Job was deleted outside of the operator
+	ReasonMissing = "Missing"
+	// Reasons for Synced condition
+	ReasonInSync            = "InSync"
+	ReasonChangesNotApplied = "ChangesNotApplied"
+	ReasonAwaitingApproval  = "AwaitingApproval"
+	// Reasons for plan approval events
+	ReasonPlanPending      = "PlanPending"
+	ReasonPlanApproved     = "PlanApproved"
+	ReasonApprovalRejected = "ApprovalRejected"
+	// StatusScriptAvailable is set when the script and dependencies of Glue Job exist in S3
+	StatusScriptAvailable = "ScriptAvailable"
+	// Reasons for ScriptAvailable condition
+	ReasonArtifactsFound       = "ArtifactsFound"
+	ReasonArtifactsMissing     = "ArtifactsMissing"
+	ReasonArtifactsCheckFailed = "ArtifactsCheckFailed"
+	// ReasonScriptUploaded is recorded when the script from script source is uploaded to S3
+	ReasonScriptUploaded = "ScriptUploaded"
+	// ReasonModulesUploaded is recorded when the bundle of Python modules is uploaded to S3
+	ReasonModulesUploaded = "ModulesUploaded"
+	// ReasonScriptChanged is recorded when the ETag or version of the script in S3 changes
+	ReasonScriptChanged = "ScriptChanged"
+	// Reasons for smoke test events
+	ReasonSmokeTestStarted = "SmokeTestStarted"
+	ReasonSmokeTestFailed  = "SmokeTestFailed"
+	// Reasons for source control events
+	ReasonSourceControlPulled = "SourceControlPulled"
+	ReasonSourceControlPushed = "SourceControlPushed"
+	// Reasons for Ready condition of GlueScriptSource
+	ReasonFetched     = "Fetched"
+	ReasonFetchFailed = "FetchFailed"
+	// StatusPlaintextSecrets is set when default arguments get values from Secrets
+	StatusPlaintextSecrets = "PlaintextSecrets"
+	// ReasonSecretArguments is the reason of PlaintextSecrets condition
+	ReasonSecretArguments = "SecretValuesInArguments"
+	// ReasonSecretsInvalid is set when secrets of Glue Job don't exist or the role can't read them
+	ReasonSecretsInvalid = "SecretsInvalid"
+	// Reasons for OwnershipConflict and Conflict conditions
+	ReasonOwned         = "Owned"
+	ReasonOwnedByOther  = "OwnedByOther"
+	ReasonUniqueName    = "UniqueName"
+	ReasonDuplicateName = "DuplicateName"
 )
@@ -0,0 +1,253 @@
+package gc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+	"github.com/90poe/glue-jobs-operator/internal/metrics"
+	"github.com/90poe/glue-jobs-operator/internal/naming"
+)
+
+// OrphanCollector periodically looks for Glue Jobs owned by the operator in this cluster,
+// which have no matching GlueJob (e.g. GlueJob was force deleted or operator was down during deletion).
+// Orphaned Glue Jobs are reported and, if enabled, deleted after the grace period.
+type OrphanCollector struct {
+	client.Reader
+	// AWS provides AWS clients for AWSProviderConfigs
+	AWS *awsclient.Provider
+	// Config holds the operator config, which is reloaded on changes of the config file
+	Config *config.Store
+	// config is the config of the current collection
+	config config.OperatorConfig
+	log    logr.Logger
+	// firstSeen is the time Glue Job was first seen orphaned
+	firstSeen map[string]time.Time
+	// watched caches, if GlueJob namespaces are watched by the operator during collection
+	watched map[string]bool
+}
+
+// SetupWithManager adds the collector to the Manager, it runs only on the leader
+// (of the shard, which collects orphaned Glue Jobs)
+func (c *OrphanCollector) SetupWithManager(mgr ctrl.Manager) error {
+	c.config = c.Config.Get()
+	if !c.config.CollectsOrphans() {
+		return nil
+	}
+	c.log = mgr.GetLogger().WithName("orphan-gc")
+	c.firstSeen = make(map[string]time.Time)
+	return mgr.Add(c)
+}
+
+// NeedLeaderElection makes collector run only on the leader
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Start will run the collector until context is done
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	c.log.V(0).Info("Starting orphaned Glue Jobs garbage collector",
+		"interval", c.config.OrphanGCInterval, "gracePeriod", c.config.OrphanGCGracePeriod,
+		"delete", c.config.OrphanGCDelete, "dryRun", c.config.OrphanGCDryRun)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.collect(ctx); err != nil {
+			metrics.OrphanGCErrors.Inc()
+			c.log.V(0).Error(err, "Failed to collect orphaned Glue Jobs")
+		}
+	}, c.config.OrphanGCInterval)
+	return nil
+}
+
+// collect will find orphaned Glue Jobs and delete them, if they are orphaned longer than grace period
+func (c *OrphanCollector) collect(ctx context.Context) error {
+	// interval is fixed, but the rest of the config may be reloaded
+	c.config = c.Config.Get()
+	c.watched = make(map[string]bool)
+	glueJobs := &awsv1alpha1.GlueJobList{}
+	err := c.List(ctx, glueJobs)
+	if err != nil {
+		return err
+	}
+	uids := make(map[string]struct{}, len(glueJobs.Items))
+	names := make(map[string]struct{}, len(glueJobs.Items))
+	for i := range glueJobs.Items {
+		uids[string(glueJobs.Items[i].UID)] = struct{}{}
+		name, err := naming.JobName(c.config, &glueJobs.Items[i])
+		if err != nil {
+			return err
+		}
+		names[name] = struct{}{}
+	}
+
+	// Glue Jobs are looked for in every account and region known to the operator
+	providerConfigs := &awsv1alpha1.AWSProviderConfigList{}
+	err = c.List(ctx, providerConfigs)
+	if err != nil {
+		return err
+	}
+	providerConfigNames := []string{""}
+	for i := range providerConfigs.Items {
+		providerConfigNames = append(providerConfigNames, providerConfigs.Items[i].Name)
+	}
+
+	now := time.Now()
+	errs := make([]error, 0)
+	orphans := make(map[string]time.Time)
+	seen := make(map[string]struct{})
+	collect := func(clients *awsclient.Clients) {
+		// several AWSProviderConfigs and placements may point to the same account and region
+		location := clients.AccountID + "/" + clients.Region
+		if _, ok := seen[location]; ok {
+			return
+		}
+		seen[location] = struct{}{}
+		err := c.collectLocation(ctx, glue.NewOwnedJobs(ctx, c.config, clients), location, uids, names, now, orphans)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range providerConfigNames {
+		clients, err := c.AWS.ForProviderConfig(ctx, name, "")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		collect(clients)
+	}
+	// placements override region or role of AWSProviderConfigs, placements removed from the spec
+	// are still in the status, until their Glue Jobs are deleted
+	for i := range glueJobs.Items {
+		gj := &glueJobs.Items[i]
+		for _, placement := range placementOverrides(gj) {
+			clients, err := c.AWS.ForPlacementOverride(ctx, gj, placement)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			collect(clients)
+		}
+	}
+	c.firstSeen = orphans
+	metrics.OrphanedJobs.Set(float64(len(orphans)))
+	return kerrors.NewAggregate(errs)
+}
+
+// placementOverrides will return placements of GlueJob in the spec and in the status, which override region
+// or assume role of AWSProviderConfig
+func placementOverrides(gj *awsv1alpha1.GlueJob) []*awsv1alpha1.GlueJobPlacement {
+	overrides := make([]*awsv1alpha1.GlueJobPlacement, 0)
+	add := func(placement *awsv1alpha1.GlueJobPlacement) {
+		if placement.Region != "" || placement.AssumeRole != nil {
+			overrides = append(overrides, placement)
+		}
+	}
+	for i := range gj.Spec.Placements {
+		add(&gj.Spec.Placements[i])
+	}
+	for i := range gj.Status.Placements {
+		add(&gj.Status.Placements[i].Placement)
+	}
+	return overrides
+}
+
+// watchesNamespace will return true, if GlueJobs in namespace are watched by the operator
+func (c *OrphanCollector) watchesNamespace(ctx context.Context, namespace string) (bool, error) {
+	if c.config.WatchNamespaceSelector == "" && len(c.config.WatchNamespaces) == 0 {
+		return true, nil
+	}
+	if namespace == "" {
+		// Glue Jobs without namespace tag can't be attributed to a namespace
+		return false, nil
+	}
+	watched, ok := c.watched[namespace]
+	if ok {
+		return watched, nil
+	}
+	watched, err := c.config.WatchesNamespace(ctx, c, namespace)
+	if errors.IsNotFound(err) {
+		// namespace is gone, so is GlueJob, but the selector can't tell whose Glue Job it was
+		watched, err = false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	c.watched[namespace] = watched
+	return watched, nil
+}
+
+// isOrphan will return true, if Glue Job has no matching GlueJob and is collected by this instance
+func (c *OrphanCollector) isOrphan(ctx context.Context, job glue.OwnedJob, uids, names map[string]struct{}) (bool, error) {
+	// Glue Job is matched by owner UID or, for jobs without owner tags, by name
+	if _, ok := uids[job.UID]; ok {
+		return false, nil
+	}
+	if _, ok := names[job.Name]; ok {
+		return false, nil
+	}
+	// Glue Jobs of GlueJobs in namespaces not watched by the operator belong to other instances
+	watched, err := c.watchesNamespace(ctx, job.Namespace)
+	if err != nil || !watched {
+		return false, err
+	}
+	// orphaned Glue Jobs of hash based shards are collected by the shard of their GlueJob
+	if c.config.ShardCount > 0 && !c.config.InShard(job.Namespace, job.OwnerName, nil) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// collectLocation will find orphaned Glue Jobs in single account and region and delete them,
+// if they are orphaned longer than grace period. Orphans are recorded in orphans
+func (c *OrphanCollector) collectLocation(ctx context.Context, ownedJobs *glue.OwnedJobs, location string,
+	uids, names map[string]struct{}, now time.Time, orphans map[string]time.Time) error {
+	jobs, err := ownedJobs.List()
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0)
+	for _, job := range jobs {
+		orphan, err := c.isOrphan(ctx, job, uids, names)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !orphan {
+			continue
+		}
+		key := location + "/" + job.Name
+		firstSeen, ok := c.firstSeen[key]
+		if !ok {
+			firstSeen = now
+		}
+		orphans[key] = firstSeen
+		log := c.log.WithValues("location", location, "job", job.Name,
+			"namespace", job.Namespace, "name", job.OwnerName, "uid", job.UID)
+		if !c.config.OrphanGCDelete || now.Sub(firstSeen) < c.config.OrphanGCGracePeriod {
+			log.V(0).Info("Found orphaned Glue Job", "orphanedSince", firstSeen)
+			continue
+		}
+		if c.config.OrphanGCDryRun || c.config.ObserveOnly {
+			log.V(0).Info("Would delete orphaned Glue Job (dry run)", "orphanedSince", firstSeen)
+			continue
+		}
+		log.V(0).Info("Deleting orphaned Glue Job", "orphanedSince", firstSeen)
+		if err := ownedJobs.Delete(job.Name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		metrics.OrphanedJobsDeleted.Inc()
+		delete(orphans, key)
+	}
+	return kerrors.NewAggregate(errs)
+}
@@ -0,0 +1,126 @@
+package gc
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/glue"
+)
+
+func TestIsOrphan(t *testing.T) {
+	uids := map[string]struct{}{"uid-1": {}}
+	names := map[string]struct{}{"prod-team-a-legacy": {}}
+	tests := []struct {
+		name   string
+		config config.OperatorConfig
+		job    glue.OwnedJob
+		orphan bool
+	}{
+		{
+			name: "GlueJob with the UID exists",
+			job:  glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-1"},
+		},
+		{
+			name: "GlueJob with the name exists",
+			job:  glue.OwnedJob{Name: "prod-team-a-legacy"},
+		},
+		{
+			name:   "GlueJob is gone",
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+			orphan: true,
+		},
+		{
+			name:   "job without owner tags is gone",
+			job:    glue.OwnedJob{Name: "prod-team-a-old"},
+			orphan: true,
+		},
+		{
+			name:   "namespace is watched",
+			config: config.OperatorConfig{WatchNamespaces: []string{"team-a"}},
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+			orphan: true,
+		},
+		{
+			name:   "namespace is not watched",
+			config: config.OperatorConfig{WatchNamespaces: []string{"team-b"}},
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+		},
+		{
+			name:   "job without namespace with watched namespaces",
+			config: config.OperatorConfig{WatchNamespaces: []string{"team-a"}},
+			job:    glue.OwnedJob{Name: "prod-team-a-old"},
+		},
+		{
+			name:   "namespace matches selector",
+			config: config.OperatorConfig{WatchNamespaceSelector: "team=a"},
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+			orphan: true,
+		},
+		{
+			name:   "namespace doesn't match selector",
+			config: config.OperatorConfig{WatchNamespaceSelector: "team=b"},
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+		},
+		{
+			name:   "namespace of selector is gone",
+			config: config.OperatorConfig{WatchNamespaceSelector: "team=a"},
+			job:    glue.OwnedJob{Name: "prod-team-c-etl", Namespace: "team-c", OwnerName: "etl", UID: "uid-2"},
+		},
+		{
+			name:   "GlueJob in the hash shard",
+			config: config.OperatorConfig{ShardName: "s", ShardCount: 2, ShardIndex: config.ShardIndex("team-a", "etl", 2)},
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+			orphan: true,
+		},
+		{
+			name:   "GlueJob in another hash shard",
+			config: config.OperatorConfig{ShardName: "s", ShardCount: 2, ShardIndex: 1 - config.ShardIndex("team-a", "etl", 2)},
+			job:    glue.OwnedJob{Name: "prod-team-a-etl", Namespace: "team-a", OwnerName: "etl", UID: "uid-2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}}
+			c := &OrphanCollector{
+				Reader:  fake.NewClientBuilder().WithObjects(namespace).Build(),
+				config:  tt.config,
+				watched: make(map[string]bool),
+			}
+			orphan, err := c.isOrphan(context.Background(), tt.job, uids, names)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if orphan != tt.orphan {
+				t.Errorf("expected orphan %v, got %v", tt.orphan, orphan)
+			}
+		})
+	}
+}
+
+func TestPlacementOverrides(t *testing.T) {
+	role := &awsv1alpha1.AWSAssumeRole{RoleARN: "arn:aws:iam::123456789012:role/glue"}
+	gj := &awsv1alpha1.GlueJob{
+		Spec: awsv1alpha1.GlueJobSpec{Placements: []awsv1alpha1.GlueJobPlacement{
+			{Name: "primary"},
+			{Name: "dr", Region: "eu-central-1"},
+		}},
+		Status: awsv1alpha1.GlueJobStatus{Placements: []awsv1alpha1.GlueJobPlacementStatus{
+			{Name: "primary", Placement: awsv1alpha1.GlueJobPlacement{Name: "primary"}},
+			{Name: "removed", Placement: awsv1alpha1.GlueJobPlacement{Name: "removed", AssumeRole: role}},
+		}},
+	}
+	overrides := placementOverrides(gj)
+	names := make([]string, 0, len(overrides))
+	for _, placement := range overrides {
+		names = append(names, placement.Name)
+	}
+	if len(names) != 2 || names[0] != "dr" || names[1] != "removed" {
+		t.Errorf("expected placements [dr removed], got %v", names)
+	}
+}
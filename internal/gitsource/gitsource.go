@@ -0,0 +1,314 @@
+package gitsource
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Keys of Secret with credentials of Git repository
+const (
+	// UsernameKey and PasswordKey are the HTTP basic auth credentials (e.g. user and access token)
+	UsernameKey = "username"
+	PasswordKey = "password"
+	// IdentityKey is the SSH private key
+	IdentityKey = "identity"
+	// KnownHostsKey are the SSH known hosts, they're required with identity
+	KnownHostsKey = "known_hosts"
+)
+
+// maxSnapshotSize is the maximum total size of files under the path of Git repository,
+// snapshots are kept in memory of the operator
+var maxSnapshotSize int64 = 64 << 20
+
+// maxCloneSize is the maximum size of the pack fetched from Git repository and of the objects unpacked
+// from it, clones are kept in memory of the operator until the files are read
+var maxCloneSize int64 = 256 << 20
+
+// allowLocalURLs allows repositories in local paths, which only tests use
+var allowLocalURLs = false
+
+// Snapshot are the files of Git repository path at a commit
+type Snapshot struct {
+	// Revision is the hash of the resolved ref, it's the tag object for annotated tags
+	Revision string
+	// Commit is the hash of the commit
+	Commit string
+	// Generation is the generation of GlueScriptSource the snapshot was fetched for
+	Generation int64
+	// Files are the files under the path by path relative to it
+	Files map[string][]byte
+}
+
+// Auth will return Git auth method from the Secret data, nil data means anonymous access
+func Auth(data map[string][]byte) (transport.AuthMethod, error) {
+	if data == nil {
+		return nil, nil
+	}
+	if identity, ok := data[IdentityKey]; ok {
+		knownHosts, ok := data[KnownHostsKey]
+		if !ok {
+			return nil, fmt.Errorf("secret with %s must have %s", IdentityKey, KnownHostsKey)
+		}
+		username := string(data[UsernameKey])
+		if username == "" {
+			username = "git"
+		}
+		auth, err := gitssh.NewPublicKeys(username, identity, string(data[PasswordKey]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", IdentityKey, err)
+		}
+		auth.HostKeyCallback, err = hostKeyCallback(knownHosts)
+		if err != nil {
+			return nil, err
+		}
+		return auth, nil
+	}
+	if _, ok := data[PasswordKey]; ok {
+		return &githttp.BasicAuth{Username: string(data[UsernameKey]), Password: string(data[PasswordKey])}, nil
+	}
+	return nil, fmt.Errorf("secret must have %s or %s", IdentityKey, PasswordKey)
+}
+
+// hostKeyCallback will return SSH host key callback of known hosts
+func hostKeyCallback(knownHosts []byte) (ssh.HostKeyCallback, error) {
+	// knownhosts only reads files
+	f, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", KnownHostsKey, err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(knownHosts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", KnownHostsKey, err)
+	}
+	callback, err := knownhosts.New(f.Name())
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", KnownHostsKey, err)
+	}
+	return callback, nil
+}
+
+// ValidateURL will return error, unless the URL of Git repository is HTTPS, SSH or Git protocol URL
+func ValidateURL(url string) error {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return fmt.Errorf("invalid URL %s: %w", url, err)
+	}
+	switch endpoint.Protocol {
+	case "https", "ssh", "git":
+		return nil
+	case "file":
+		if allowLocalURLs {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported protocol %s of URL %s, use https, ssh or git", endpoint.Protocol, url)
+}
+
+// ResolveRef will return the name and the hash of branch or tag ref in the remote repository
+func ResolveRef(ctx context.Context, url, ref string, auth transport.AuthMethod) (plumbing.ReferenceName, string, error) {
+	if err := ValidateURL(url); err != nil {
+		return "", "", err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: "origin", URLs: []string{url}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list refs of %s: %w", url, err)
+	}
+	candidates := []plumbing.ReferenceName{
+		plumbing.ReferenceName(ref),
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.NewTagReferenceName(ref),
+	}
+	for _, name := range candidates {
+		for _, r := range refs {
+			if r.Name() == name && r.Type() == plumbing.HashReference {
+				return name, r.Hash().String(), nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("ref %s not found in %s", ref, url)
+}
+
+// Fetch will return the files under dir of the remote repository at ref, their total size is limited,
+// because snapshots are kept in memory
+func Fetch(ctx context.Context, url string, ref plumbing.ReferenceName, dir string,
+	auth transport.AuthMethod) (*Snapshot, error) {
+	if err := ValidateURL(url); err != nil {
+		return nil, err
+	}
+	repo, err := git.CloneContext(ctx, &limitedStorage{Storage: memory.NewStorage()}, nil, &git.CloneOptions{
+		URL:           url,
+		Auth:          auth,
+		ReferenceName: ref,
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone %s: %w", url, err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s of %s: %w", ref, url, err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s of %s: %w", head.Hash(), url, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of commit %s: %w", head.Hash(), err)
+	}
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	if dir != "" {
+		tree, err = tree.Tree(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s at commit %s: %w", dir, head.Hash(), err)
+		}
+	}
+	files := make(map[string][]byte)
+	var size int64
+	err = tree.Files().ForEach(func(f *object.File) error {
+		size += f.Size
+		if size > maxSnapshotSize {
+			return fmt.Errorf("files are larger than %d bytes", maxSnapshotSize)
+		}
+		reader, err := f.Reader()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		files[f.Name] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at commit %s: %w", dir, head.Hash(), err)
+	}
+	return &Snapshot{Commit: head.Hash().String(), Files: files}, nil
+}
+
+// limitedStorage is the in-memory storage of cloned repository, which fails the clone once the fetched pack
+// or the objects unpacked from it are larger than maxCloneSize
+type limitedStorage struct {
+	*memory.Storage
+	size int64
+}
+
+// PackfileWriter will return the writer of the fetched pack, the objects are unpacked when it's closed,
+// so the transfer is aborted before too large pack is unpacked
+func (s *limitedStorage) PackfileWriter() (io.WriteCloser, error) {
+	return &packWriter{storage: s}, nil
+}
+
+// SetEncodedObject will store the object, unless the objects are larger than maxCloneSize
+func (s *limitedStorage) SetEncodedObject(obj plumbing.EncodedObject) (plumbing.Hash, error) {
+	s.size += obj.Size()
+	if s.size > maxCloneSize {
+		return plumbing.ZeroHash, fmt.Errorf("objects are larger than %d bytes", maxCloneSize)
+	}
+	return s.Storage.SetEncodedObject(obj)
+}
+
+// packWriter buffers the fetched pack up to maxCloneSize
+type packWriter struct {
+	storage *limitedStorage
+	pack    bytes.Buffer
+	err     error
+}
+
+func (w *packWriter) Write(p []byte) (int, error) {
+	if int64(w.pack.Len()+len(p)) > maxCloneSize {
+		w.err = fmt.Errorf("pack is larger than %d bytes", maxCloneSize)
+		return 0, w.err
+	}
+	return w.pack.Write(p)
+}
+
+// Close will unpack the objects of the pack into the storage, unless writing of the pack failed
+func (w *packWriter) Close() error {
+	if w.err != nil {
+		return nil
+	}
+	parser, err := packfile.NewParserWithStorage(packfile.NewScanner(&w.pack), w.storage)
+	if err != nil {
+		return err
+	}
+	_, err = parser.Parse()
+	return err
+}
+
+// ErrNotSynced is returned for GlueScriptSource, which files aren't fetched yet
+var ErrNotSynced = errors.New("GlueScriptSource isn't synced yet")
+
+// Cache holds the last fetched snapshots of GlueScriptSources
+type Cache struct {
+	mu        sync.RWMutex
+	snapshots map[types.NamespacedName]*Snapshot
+}
+
+// NewCache will return empty cache of snapshots
+func NewCache() *Cache {
+	return &Cache{snapshots: make(map[types.NamespacedName]*Snapshot)}
+}
+
+// Get will return the snapshot of GlueScriptSource, nil if it isn't fetched
+func (c *Cache) Get(key types.NamespacedName) *Snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshots[key]
+}
+
+// File will return the file at the commit of GlueScriptSource snapshot
+func (c *Cache) File(key types.NamespacedName, name string) ([]byte, string, error) {
+	snapshot := c.Get(key)
+	if snapshot == nil {
+		return nil, "", fmt.Errorf("%s: %w", key, ErrNotSynced)
+	}
+	content, ok := snapshot.Files[strings.TrimPrefix(path.Clean("/"+name), "/")]
+	if !ok {
+		return nil, "", fmt.Errorf("file %s not found in GlueScriptSource %s at commit %s", name, key, snapshot.Commit)
+	}
+	return content, snapshot.Commit, nil
+}
+
+// Set will set the snapshot of GlueScriptSource
+func (c *Cache) Set(key types.NamespacedName, snapshot *Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshots[key] = snapshot
+}
+
+// Delete will delete the snapshot of deleted GlueScriptSource
+func (c *Cache) Delete(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.snapshots, key)
+}
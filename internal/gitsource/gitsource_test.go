@@ -0,0 +1,243 @@
+package gitsource
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// testRepo is a work repository pushing to a local bare repository
+type testRepo struct {
+	t    *testing.T
+	dir  string
+	bare string
+	repo *git.Repository
+}
+
+// newTestRepo will return work repository with origin in the local bare repository
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	allowLocalURLs = true
+	bare := t.TempDir()
+	_, err := git.PlainInit(bare, true)
+	if err != nil {
+		t.Fatalf("failed to init bare repository: %v", err)
+	}
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repository: %v", err)
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: "origin", URLs: []string{bare}})
+	if err != nil {
+		t.Fatalf("failed to create remote: %v", err)
+	}
+	return &testRepo{t: t, dir: dir, bare: bare, repo: repo}
+}
+
+// commit will commit files and push the master branch and tags to the bare repository
+func (r *testRepo) commit(files map[string]string, tag string) string {
+	r.t.Helper()
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatalf("failed to get worktree: %v", err)
+	}
+	for name, content := range files {
+		filePath := filepath.Join(r.dir, name)
+		if err = os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err = os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+		if _, err = worktree.Add(name); err != nil {
+			r.t.Fatalf("failed to add %s: %v", name, err)
+		}
+	}
+	signature := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	hash, err := worktree.Commit("update", &git.CommitOptions{Author: signature})
+	if err != nil {
+		r.t.Fatalf("failed to commit: %v", err)
+	}
+	if tag != "" {
+		_, err = r.repo.CreateTag(tag, hash, &git.CreateTagOptions{Tagger: signature, Message: tag})
+		if err != nil {
+			r.t.Fatalf("failed to tag: %v", err)
+		}
+	}
+	err = r.repo.Push(&git.PushOptions{
+		RefSpecs: []gitconfig.RefSpec{"refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"},
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		r.t.Fatalf("failed to push: %v", err)
+	}
+	return hash.String()
+}
+
+func TestFetchBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	first := repo.commit(map[string]string{
+		"jobs/etl.py":        "print('v1')",
+		"jobs/lib/common.py": "X = 1",
+		"README.md":          "docs",
+	}, "")
+
+	ctx := context.Background()
+	ref, revision, err := ResolveRef(ctx, repo.bare, "master", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revision != first {
+		t.Errorf("expected revision %s, got %s", first, revision)
+	}
+	snapshot, err := Fetch(ctx, repo.bare, ref, "/jobs/", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.Commit != first {
+		t.Errorf("expected commit %s, got %s", first, snapshot.Commit)
+	}
+	if len(snapshot.Files) != 2 || string(snapshot.Files["etl.py"]) != "print('v1')" ||
+		string(snapshot.Files["lib/common.py"]) != "X = 1" {
+		t.Errorf("unexpected files %v", snapshot.Files)
+	}
+
+	second := repo.commit(map[string]string{"jobs/etl.py": "print('v2')"}, "")
+	_, revision, err = ResolveRef(ctx, repo.bare, "master", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revision != second {
+		t.Errorf("expected revision %s after push, got %s", second, revision)
+	}
+	snapshot, err = Fetch(ctx, repo.bare, ref, "jobs", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.Commit != second || string(snapshot.Files["etl.py"]) != "print('v2')" {
+		t.Errorf("expected etl.py of commit %s, got %s of %s", second, snapshot.Files["etl.py"], snapshot.Commit)
+	}
+}
+
+func TestFetchTag(t *testing.T) {
+	repo := newTestRepo(t)
+	tagged := repo.commit(map[string]string{"etl.py": "print('release')"}, "v1.0.0")
+	repo.commit(map[string]string{"etl.py": "print('dev')"}, "")
+
+	ctx := context.Background()
+	ref, _, err := ResolveRef(ctx, repo.bare, "v1.0.0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	snapshot, err := Fetch(ctx, repo.bare, ref, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if snapshot.Commit != tagged || string(snapshot.Files["etl.py"]) != "print('release')" {
+		t.Errorf("expected etl.py of tagged commit %s, got %s of %s", tagged, snapshot.Files["etl.py"], snapshot.Commit)
+	}
+
+	_, _, err = ResolveRef(ctx, repo.bare, "v2.0.0", nil)
+	if err == nil {
+		t.Error("expected error of missing ref")
+	}
+}
+
+func TestFetchTooLarge(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit(map[string]string{"etl.py": "print('etl')", "lib.py": "X = 1"}, "")
+	defer func(size int64) { maxSnapshotSize = size }(maxSnapshotSize)
+	maxSnapshotSize = 15
+
+	ctx := context.Background()
+	ref, _, err := ResolveRef(ctx, repo.bare, "master", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = Fetch(ctx, repo.bare, ref, "", nil); err == nil {
+		t.Error("expected error of files larger than the limit")
+	}
+}
+
+func TestFetchPackTooLarge(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit(map[string]string{"etl.py": "print('etl')", "lib.py": "X = 1"}, "")
+	defer func(size int64) { maxCloneSize = size }(maxCloneSize)
+	maxCloneSize = 100
+
+	ctx := context.Background()
+	ref, _, err := ResolveRef(ctx, repo.bare, "master", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = Fetch(ctx, repo.bare, ref, "", nil); err == nil || !strings.Contains(err.Error(), "pack is larger") {
+		t.Errorf("expected error of pack larger than the limit, got %v", err)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	defer func(allow bool) { allowLocalURLs = allow }(allowLocalURLs)
+	allowLocalURLs = false
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://github.com/org/repo.git", valid: true},
+		{url: "ssh://git@github.com/org/repo.git", valid: true},
+		{url: "git@github.com:org/repo.git", valid: true},
+		{url: "git://example.com/repo.git", valid: true},
+		{url: "http://github.com/org/repo.git"},
+		{url: "file:///etc/repo"},
+		{url: "/var/run/secrets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if err := ValidateURL(tt.url); (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestCacheFile(t *testing.T) {
+	cache := NewCache()
+	key := types.NamespacedName{Namespace: "infra", Name: "scripts"}
+	_, _, err := cache.File(key, "etl.py")
+	if !errors.Is(err, ErrNotSynced) {
+		t.Errorf("expected not synced error, got %v", err)
+	}
+	cache.Set(key, &Snapshot{Commit: "abc", Files: map[string][]byte{"jobs/etl.py": []byte("print()")}})
+	content, commit, err := cache.File(key, "/jobs/etl.py")
+	if err != nil || commit != "abc" || string(content) != "print()" {
+		t.Errorf("unexpected file %s of commit %s: %v", content, commit, err)
+	}
+	if _, _, err = cache.File(key, "missing.py"); err == nil {
+		t.Error("expected error of missing file")
+	}
+}
+
+func TestAuth(t *testing.T) {
+	auth, err := Auth(nil)
+	if err != nil || auth != nil {
+		t.Errorf("expected anonymous access, got %v: %v", auth, err)
+	}
+	auth, err = Auth(map[string][]byte{UsernameKey: []byte("bot"), PasswordKey: []byte("token")})
+	if err != nil || auth == nil || auth.Name() != "http-basic-auth" {
+		t.Errorf("expected basic auth, got %v: %v", auth, err)
+	}
+	if _, err = Auth(map[string][]byte{IdentityKey: []byte("key")}); err == nil {
+		t.Error("expected error of identity without known hosts")
+	}
+	if _, err = Auth(map[string][]byte{UsernameKey: []byte("bot")}); err == nil {
+		t.Error("expected error of secret without credentials")
+	}
+}
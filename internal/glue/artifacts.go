@@ -0,0 +1,121 @@
+package glue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const s3Scheme = "s3://"
+
+// artifactArguments are default arguments with comma separated S3 paths of job dependencies
+var artifactArguments = []string{extraPyFilesArg, "--extra-jars", "--extra-files", rayArgS3PyModules, rayArgWorkingDir}
+
+// HeadObjectAPI is the part of S3 client, which checks that artifacts exist
+type HeadObjectAPI interface {
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+}
+
+// S3API is the part of S3 client used by Glue Job, it checks artifacts, reads and uploads scripts
+type S3API interface {
+	HeadObjectAPI
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// Artifacts will return S3 URIs of the script and dependencies of Glue Job
+func (g *Job) Artifacts() []string {
+	return artifactURIs(g.job.Command.ScriptLocation, g.defaultArguments())
+}
+
+// MissingArtifacts will return S3 URIs of the script and dependencies of Glue Job, which don't exist
+func (g *Job) MissingArtifacts() ([]string, error) {
+	return MissingArtifacts(g.ctx, g.s3Client, g.Artifacts())
+}
+
+// artifactURIs will return S3 URIs of the script and of dependencies in default arguments,
+// other entries of dependency arguments (e.g. Python packages) are skipped
+func artifactURIs(scriptLocation string, args map[string]string) []string {
+	uris := make([]string, 0)
+	seen := make(map[string]struct{})
+	add := func(uri string) {
+		uri = strings.TrimSpace(uri)
+		if !strings.HasPrefix(uri, s3Scheme) {
+			return
+		}
+		if _, ok := seen[uri]; ok {
+			return
+		}
+		seen[uri] = struct{}{}
+		uris = append(uris, uri)
+	}
+	add(scriptLocation)
+	for _, arg := range artifactArguments {
+		for _, uri := range strings.Split(args[arg], ",") {
+			add(uri)
+		}
+	}
+	return uris
+}
+
+// MissingArtifacts will return the S3 URIs, which don't exist. Failed checks (e.g. access denied)
+// are returned as error, URIs which couldn't be checked are not reported missing
+func MissingArtifacts(ctx context.Context, client HeadObjectAPI, uris []string) ([]string, error) {
+	missing := make([]string, 0)
+	errs := make([]error, 0)
+	for _, uri := range uris {
+		bucket, key, err := parseS3URI(uri)
+		if err != nil {
+			missing = append(missing, uri)
+			continue
+		}
+		_, err = client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if isNotFound(err) {
+			missing = append(missing, uri)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to check %s: %w", uri, err))
+		}
+	}
+	return missing, kerrors.NewAggregate(errs)
+}
+
+// parseS3URI will return bucket and key of S3 URI
+func parseS3URI(uri string) (string, string, error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(uri, s3Scheme), "/")
+	if !strings.HasPrefix(uri, s3Scheme) || !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid S3 URI %s", uri)
+	}
+	return bucket, key, nil
+}
+
+// isNotFound will return true, if S3 error means the object or the bucket doesn't exist
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var notFound *s3types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var noSuchBucket *s3types.NoSuchBucket
+	if errors.As(err, &noSuchBucket) {
+		return true
+	}
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,94 @@
+package glue
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// newFakeS3 will return S3 client of S3-compatible endpoint, which has objects with paths
+// (/bucket/key) and denies access to the forbidden bucket
+func newFakeS3(t *testing.T, objects ...string) *s3.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case slices.Contains(objects, r.URL.Path):
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/forbidden/script.py":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return s3.New(s3.Options{
+		Region:           "eu-west-1",
+		BaseEndpoint:     aws.String(server.URL),
+		UsePathStyle:     true,
+		Credentials:      credentials.NewStaticCredentialsProvider("key", "secret", ""),
+		RetryMaxAttempts: 1,
+	})
+}
+
+func TestArtifactURIs(t *testing.T) {
+	uris := artifactURIs("s3://bucket/script.py", map[string]string{
+		"--extra-py-files": "s3://bucket/a.py, s3://bucket/b.zip",
+		"--extra-jars":     "s3://bucket/lib.jar",
+		"--extra-files":    "s3://bucket/a.py",
+		"--s3-py-modules":  "s3://bucket/mod.whl,pandas==2.0",
+		"--job-language":   "python",
+	})
+	expected := []string{
+		"s3://bucket/script.py",
+		"s3://bucket/a.py",
+		"s3://bucket/b.zip",
+		"s3://bucket/lib.jar",
+		"s3://bucket/mod.whl",
+	}
+	if !slices.Equal(uris, expected) {
+		t.Errorf("expected %v, got %v", expected, uris)
+	}
+}
+
+func TestMissingArtifacts(t *testing.T) {
+	client := newFakeS3(t, "/bucket/script.py", "/bucket/lib.jar")
+
+	missing, err := MissingArtifacts(context.Background(), client, []string{
+		"s3://bucket/script.py",
+		"s3://bucket/lib.jar",
+		"s3://bucket/typo.py",
+		"s3://other/script.py",
+		"s3://bucket",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"s3://bucket/typo.py", "s3://other/script.py", "s3://bucket"}
+	if !slices.Equal(missing, expected) {
+		t.Errorf("expected missing %v, got %v", expected, missing)
+	}
+}
+
+func TestMissingArtifactsCheckFailed(t *testing.T) {
+	client := newFakeS3(t, "/bucket/script.py")
+
+	missing, err := MissingArtifacts(context.Background(), client, []string{
+		"s3://bucket/script.py",
+		"s3://forbidden/script.py",
+	})
+	if err == nil {
+		t.Fatal("expected error of forbidden artifact")
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing artifacts, got %v", missing)
+	}
+}
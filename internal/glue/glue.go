@@ -2,69 +2,94 @@ package glue
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"maps"
 	"strings"
 
 	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/naming"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	awsglue "github.com/aws/aws-sdk-go-v2/service/glue"
 	"github.com/aws/aws-sdk-go-v2/service/glue/types"
-	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 const (
-	glueETL = "glueetl"
-)
-
-var (
-	// jobOwner is map which we will add to Tags so we know,
-	// that this GlueJob is owned by this operator
-	jobOwnedByOperator = map[string]string{
-		"glue-jobs-operator": "true",
-	}
+	// awsTagPrefix is prefix of tags reserved by AWS, which can't be removed
+	awsTagPrefix = "aws:"
 )
 
 type Job struct {
-	ctx       context.Context
-	job       awsv1alpha1.GlueJobSpec
-	exists    bool
-	awsClient *awsglue.Client
-	accountID string
-	region    string
+	ctx             context.Context
+	job             awsv1alpha1.GlueJobSpec
+	owner           jobOwner
+	config          config.OperatorConfig
+	exists          bool
+	live            *types.Job
+	liveTags        map[string]string
+	conflict        error
+	awsClient       *awsglue.Client
+	s3Client        S3API
+	secretsClients  SecretsClients
+	script          []byte
+	scriptCommit    string
+	pinnedScript    string
+	modules         []byte
+	modulesLocation string
+	modulesHash     string
+	accountID       string
+	region          string
 }
 
-// NewJob will return a new Job struct
-func NewJob(ctx context.Context, job awsv1alpha1.GlueJobSpec) (*Job, error) {
-	gJob := &Job{
-		ctx:    ctx,
-		job:    job,
-		exists: false,
-	}
-	// Load the Shared AWS Configuration from the Shared config file or IAM Roles
-	cfg, err := config.LoadDefaultConfig(ctx)
+// NewJob will return a new Job struct, which manages Glue Job with AWS clients
+func NewJob(ctx context.Context, glueJob *awsv1alpha1.GlueJob, cfg config.OperatorConfig,
+	clients *awsclient.Clients) (*Job, error) {
+	job := glueJob.Spec
+	// Glue Job name is either spec.name or derived from GlueJob namespace and name
+	name, err := naming.JobName(cfg, glueJob)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create an Amazon Glue service client
-	gJob.awsClient = awsglue.NewFromConfig(cfg)
-
-	// Get Account ID and Region
-	stsClient := sts.NewFromConfig(cfg)
-	result, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	job.Name = name
+	gJob := &Job{
+		ctx: ctx,
+		job: withDefaults(job),
+		owner: jobOwner{
+			Namespace: glueJob.Namespace,
+			Name:      glueJob.Name,
+			UID:       string(glueJob.UID),
+		},
+		config:    cfg,
+		exists:    false,
+		awsClient: clients.Glue,
+		s3Client:  clients.S3,
+		secretsClients: SecretsClients{
+			SecretsManager: clients.SecretsManager,
+			SSM:            clients.SSM,
+			IAM:            clients.IAM,
+		},
+		accountID: clients.AccountID,
+		region:    clients.Region,
+	}
+	err = validateSpec(&gJob.job)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call GetCallerIdentity: %w", err)
+		return nil, fmt.Errorf("invalid GlueJob %s spec: %w", job.Name, err)
 	}
-	gJob.accountID = *result.Account
-	gJob.region = cfg.Region
 
-	// check that GlueJob exists on AWS
+	// check that GlueJob exists on AWS and get current job definition, so we could compare it with the spec
 	gJob.exists, err = gJob.checkJobExistsOnAWS()
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if GlueJob %s exists on AWS: %w", job.Name, err)
 	}
+	if gJob.exists {
+		gJob.liveTags, err = gJob.getLiveTags()
+		if err != nil {
+			return nil, err
+		}
+		gJob.conflict = gJob.checkOwnership(gJob.liveTags)
+	}
 
 	return gJob, nil
 }
@@ -74,31 +99,37 @@ func (g *Job) JobExists() bool {
 	return g.exists
 }
 
+// OwnershipConflict will return OwnershipConflictError if Glue Job on AWS
+// is owned by another cluster or GlueJob and must not be mutated
+func (g *Job) OwnershipConflict() error {
+	return g.conflict
+}
+
 // CreateJob will create Glue Job
 func (g *Job) CreateJob() error {
-	command := &types.JobCommand{
-		Name:           aws.String(g.job.Command.Name),
-		PythonVersion:  aws.String(fmt.Sprintf("%v", g.job.Command.PythonVersion)),
-		ScriptLocation: aws.String(g.job.Command.ScriptLocation),
-	}
-	if strings.ToLower(g.job.Command.Name) != glueETL {
-		command.Runtime = aws.String(g.job.Command.Runtime)
-	}
+	update := g.jobUpdate()
 	input := &awsglue.CreateJobInput{
-		Name:            aws.String(g.job.Name),
-		Command:         command,
-		Role:            aws.String(g.job.Role),
-		Timeout:         aws.Int32(g.job.TimeoutInMinutes),
-		GlueVersion:     aws.String(g.job.GlueVersion),
-		NumberOfWorkers: aws.Int32(g.job.NumberOfWorkers),
-		WorkerType:      types.WorkerType(g.job.WorkerType),
-		ExecutionClass:  types.ExecutionClass(g.job.ExecutionClass),
-		ExecutionProperty: &types.ExecutionProperty{
-			MaxConcurrentRuns: g.job.ExecutionProperty.MaxConcurrentRuns,
-		},
-		MaxRetries:       g.job.MaxRetries,
-		DefaultArguments: g.job.DefaultArguments,
-		Tags:             g.getTags(),
+		Name:                    aws.String(g.job.Name),
+		Command:                 update.Command,
+		Role:                    update.Role,
+		Timeout:                 update.Timeout,
+		GlueVersion:             update.GlueVersion,
+		NumberOfWorkers:         update.NumberOfWorkers,
+		WorkerType:              update.WorkerType,
+		ExecutionClass:          update.ExecutionClass,
+		ExecutionProperty:       update.ExecutionProperty,
+		MaxRetries:              update.MaxRetries,
+		DefaultArguments:        update.DefaultArguments,
+		Description:             update.Description,
+		LogUri:                  update.LogUri,
+		Connections:             update.Connections,
+		SecurityConfiguration:   update.SecurityConfiguration,
+		NotificationProperty:    update.NotificationProperty,
+		NonOverridableArguments: update.NonOverridableArguments,
+		MaintenanceWindow:       update.MaintenanceWindow,
+		JobMode:                 update.JobMode,
+		SourceControlDetails:    update.SourceControlDetails,
+		Tags:                    g.getTags(),
 	}
 	// create job
 	_, err := g.awsClient.CreateJob(g.ctx, input)
@@ -110,30 +141,16 @@ func (g *Job) CreateJob() error {
 
 // UpdateJob will update Glue Job
 func (g *Job) UpateJob() error {
-	command := &types.JobCommand{
-		Name:           aws.String(g.job.Command.Name),
-		PythonVersion:  aws.String(fmt.Sprintf("%v", g.job.Command.PythonVersion)),
-		ScriptLocation: aws.String(g.job.Command.ScriptLocation),
-	}
-	if strings.ToLower(g.job.Command.Name) != glueETL {
-		command.Runtime = aws.String(g.job.Command.Runtime)
+	if g.conflict != nil {
+		return g.conflict
 	}
+	update := g.jobUpdate()
+	// UpdateJob overwrites job definition, but we are explicit about
+	// clearing the fields, which were removed from the spec
+	clearRemovedFields(update, g.live)
 	input := &awsglue.UpdateJobInput{
-		JobName: aws.String(g.job.Name),
-		JobUpdate: &types.JobUpdate{
-			Command:         command,
-			Role:            aws.String(g.job.Role),
-			Timeout:         aws.Int32(g.job.TimeoutInMinutes),
-			GlueVersion:     aws.String(g.job.GlueVersion),
-			NumberOfWorkers: aws.Int32(g.job.NumberOfWorkers),
-			WorkerType:      types.WorkerType(g.job.WorkerType),
-			ExecutionClass:  types.ExecutionClass(g.job.ExecutionClass),
-			ExecutionProperty: &types.ExecutionProperty{
-				MaxConcurrentRuns: g.job.ExecutionProperty.MaxConcurrentRuns,
-			},
-			MaxRetries:       g.job.MaxRetries,
-			DefaultArguments: g.job.DefaultArguments,
-		},
+		JobName:   aws.String(g.job.Name),
+		JobUpdate: update,
 	}
 	// update job
 	_, err := g.awsClient.UpdateJob(g.ctx, input)
@@ -141,9 +158,26 @@ func (g *Job) UpateJob() error {
 		return fmt.Errorf("failed to update Glue Job %s: %w", g.job.Name, err)
 	}
 	// Update tags
-	_, err = g.awsClient.TagResource(g.ctx, &awsglue.TagResourceInput{
-		ResourceArn: aws.String(fmt.Sprintf("arn:aws:glue:%s:%s:job/%s", g.region, g.accountID, g.job.Name)),
-		TagsToAdd:   g.getTags(),
+	return g.updateTags()
+}
+
+// updateTags will add tags from the spec to Glue Job and remove the tags,
+// which were removed from the spec
+func (g *Job) updateTags() error {
+	tags := g.getTags()
+	tagsToRemove := g.tagsToRemove()
+	if len(tagsToRemove) > 0 {
+		_, err := g.awsClient.UntagResource(g.ctx, &awsglue.UntagResourceInput{
+			ResourceArn:  aws.String(g.jobARN()),
+			TagsToRemove: tagsToRemove,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove Glue Job tags %s: %w", g.job.Name, err)
+		}
+	}
+	_, err := g.awsClient.TagResource(g.ctx, &awsglue.TagResourceInput{
+		ResourceArn: aws.String(g.jobARN()),
+		TagsToAdd:   tags,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update Glue Job tags %s: %w", g.job.Name, err)
@@ -151,11 +185,52 @@ func (g *Job) UpateJob() error {
 	return nil
 }
 
+// isIgnoredTag will return true if tag must never be removed by the operator:
+// operator ownership tags, AWS reserved tags and tags managed by other tooling
+func (g *Job) isIgnoredTag(key string) bool {
+	if isOwnershipTag(key) {
+		return true
+	}
+	if strings.HasPrefix(key, awsTagPrefix) {
+		return true
+	}
+	for _, prefix := range g.config.IgnoredTagPrefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getLiveTags will return tags of Glue Job from AWS
+func (g *Job) getLiveTags() (map[string]string, error) {
+	out, err := g.awsClient.GetTags(g.ctx, &awsglue.GetTagsInput{
+		ResourceArn: aws.String(g.jobARN()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Glue Job tags %s: %w", g.job.Name, err)
+	}
+	return out.Tags, nil
+}
+
+// jobARN will return ARN of Glue Job
+func (g *Job) jobARN() string {
+	return jobARN(g.region, g.accountID, g.job.Name)
+}
+
+// jobARN will return ARN of Glue Job in account and region
+func jobARN(region, accountID, name string) string {
+	return fmt.Sprintf("arn:aws:glue:%s:%s:job/%s", region, accountID, name)
+}
+
 // DeleteJob will delete Glue Job
 func (g *Job) DeleteJob() error {
 	if !g.exists {
 		return nil
 	}
+	if g.conflict != nil {
+		return g.conflict
+	}
 	// delete job
 	_, err := g.awsClient.DeleteJob(g.ctx, &awsglue.DeleteJobInput{
 		JobName: aws.String(g.job.Name),
@@ -166,38 +241,159 @@ func (g *Job) DeleteJob() error {
 	return nil
 }
 
+// StartJobRun will start a run of Glue Job with arguments overriding default arguments and return its ID
+func (g *Job) StartJobRun(arguments map[string]string) (string, error) {
+	if g.conflict != nil {
+		return "", g.conflict
+	}
+	out, err := g.awsClient.StartJobRun(g.ctx, &awsglue.StartJobRunInput{
+		JobName:   aws.String(g.job.Name),
+		Arguments: arguments,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start run of Glue Job %s: %w", g.job.Name, err)
+	}
+	return aws.ToString(out.JobRunId), nil
+}
+
+// jobUpdate will return Glue Job definition for the spec
+func (g *Job) jobUpdate() *types.JobUpdate {
+	update := &types.JobUpdate{
+		Command:         g.jobCommand(),
+		Role:            aws.String(g.job.Role),
+		Timeout:         aws.Int32(g.job.TimeoutInMinutes),
+		GlueVersion:     aws.String(g.job.GlueVersion),
+		NumberOfWorkers: aws.Int32(g.job.NumberOfWorkers),
+		WorkerType:      types.WorkerType(g.job.WorkerType),
+		ExecutionClass:  types.ExecutionClass(g.job.ExecutionClass),
+		ExecutionProperty: &types.ExecutionProperty{
+			MaxConcurrentRuns: g.job.ExecutionProperty.MaxConcurrentRuns,
+		},
+		MaxRetries:              g.job.MaxRetries,
+		DefaultArguments:        g.defaultArguments(),
+		NonOverridableArguments: g.job.NonOverridableArguments,
+		JobMode:                 types.JobMode(g.job.JobMode),
+		SourceControlDetails:    g.sourceControlDetails(),
+	}
+	if g.job.Description != "" {
+		update.Description = aws.String(g.job.Description)
+	}
+	if g.job.LogURI != "" {
+		update.LogUri = aws.String(g.job.LogURI)
+	}
+	if len(g.job.Connections) > 0 {
+		update.Connections = &types.ConnectionsList{
+			Connections: g.job.Connections,
+		}
+	}
+	if g.job.SecurityConfiguration != "" {
+		update.SecurityConfiguration = aws.String(g.job.SecurityConfiguration)
+	}
+	if g.job.NotificationProperty != nil {
+		update.NotificationProperty = &types.NotificationProperty{
+			NotifyDelayAfter: g.job.NotificationProperty.NotifyDelayAfter,
+		}
+	}
+	if g.job.MaintenanceWindow != "" {
+		update.MaintenanceWindow = aws.String(g.job.MaintenanceWindow)
+	}
+	return update
+}
+
+// clearRemovedFields will set empty values on the optional fields of the update,
+// which are not in the spec anymore, but are still set on AWS
+func clearRemovedFields(update *types.JobUpdate, live *types.Job) {
+	if live == nil {
+		return
+	}
+	if update.Description == nil && aws.ToString(live.Description) != "" {
+		update.Description = aws.String("")
+	}
+	if update.LogUri == nil && aws.ToString(live.LogUri) != "" {
+		update.LogUri = aws.String("")
+	}
+	if update.Connections == nil && live.Connections != nil && len(live.Connections.Connections) > 0 {
+		update.Connections = &types.ConnectionsList{Connections: []string{}}
+	}
+	if update.SecurityConfiguration == nil && aws.ToString(live.SecurityConfiguration) != "" {
+		update.SecurityConfiguration = aws.String("")
+	}
+	if update.NotificationProperty == nil && live.NotificationProperty != nil {
+		update.NotificationProperty = &types.NotificationProperty{}
+	}
+	if update.NonOverridableArguments == nil && len(live.NonOverridableArguments) > 0 {
+		update.NonOverridableArguments = map[string]string{}
+	}
+	if update.DefaultArguments == nil && len(live.DefaultArguments) > 0 {
+		update.DefaultArguments = map[string]string{}
+	}
+	if update.MaintenanceWindow == nil && aws.ToString(live.MaintenanceWindow) != "" {
+		update.MaintenanceWindow = aws.String("")
+	}
+}
+
+// jobCommand will return Glue Job command for the kind of the job
+func (g *Job) jobCommand() *types.JobCommand {
+	command := &types.JobCommand{
+		Name:           aws.String(g.job.Command.Name),
+		PythonVersion:  aws.String(fmt.Sprintf("%v", g.job.Command.PythonVersion)),
+		ScriptLocation: aws.String(g.job.Command.ScriptLocation),
+	}
+	if isRayJob(&g.job) {
+		// Ray jobs support only single Python version, which comes with the Ray runtime
+		command.PythonVersion = aws.String(rayPythonVersion)
+		command.Runtime = aws.String(g.job.Command.Runtime)
+	} else if strings.ToLower(g.job.Command.Name) != glueETL && g.job.Command.Runtime != "" {
+		command.Runtime = aws.String(g.job.Command.Runtime)
+	}
+	return command
+}
+
+// defaultArguments will return default arguments for Glue Job merged with arguments
+// derived from kind specific settings and with ARNs of secrets
+func (g *Job) defaultArguments() map[string]string {
+	derived := secretArguments(g.job.AWSSecrets)
+	if isRayJob(&g.job) {
+		maps.Copy(derived, rayArguments(g.job.Ray))
+	}
+	if len(derived) == 0 {
+		return g.job.DefaultArguments
+	}
+	args := make(map[string]string, len(g.job.DefaultArguments)+len(derived))
+	maps.Copy(args, g.job.DefaultArguments)
+	maps.Copy(args, derived)
+	return args
+}
+
 // getTags will return tags for Glue Job with merged required tags for operator
 func (g *Job) getTags() map[string]string {
-	tags := make(map[string]string, len(g.job.Tags)+len(jobOwnedByOperator))
+	ownershipTags := g.ownershipTags()
+	tags := make(map[string]string, len(g.job.Tags)+len(ownershipTags))
 	maps.Copy(tags, g.job.Tags)
-	maps.Copy(tags, jobOwnedByOperator)
+	maps.Copy(tags, ownershipTags)
 	return tags
 }
 
-func (g *Job) checkJobExistsOnAWS() (bool, error) {
-	// get all jobs paginator
-	jobsPaginator := awsglue.NewListJobsPaginator(g.awsClient, &awsglue.ListJobsInput{
-		MaxResults: aws.Int32(100),
-		Tags:       jobOwnedByOperator,
+// getJob will return Glue Job definition from AWS
+func (g *Job) getJob() (*types.Job, error) {
+	out, err := g.awsClient.GetJob(g.ctx, &awsglue.GetJobInput{
+		JobName: aws.String(g.job.Name),
 	})
-	// Iterate through all jobs in paginator
-	for {
-		if !jobsPaginator.HasMorePages() {
-			// no more pages, break
-			break
-		}
-		jobsOut, err := jobsPaginator.NextPage(g.ctx)
-		if err != nil {
-			return false, fmt.Errorf("failed to get next page from Jobs paginator: %w", err)
-		}
-		// we have 2 criteria to match a job:
-		// 1. job name
-		// 2. job tags
-		for _, jobName := range jobsOut.JobNames {
-			if jobName == g.job.Name {
-				return true, nil
-			}
-		}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Glue Job %s: %w", g.job.Name, err)
+	}
+	return out.Job, nil
+}
+
+// checkJobExistsOnAWS will return true and get the live Glue Job, if Glue Job with the name exists.
+// It's looked up by the name rather than the owner tag, because the tag may be removed outside
+// of the operator, while the name is still taken
+func (g *Job) checkJobExistsOnAWS() (bool, error) {
+	var err error
+	g.live, err = g.getJob()
+	var notFound *types.EntityNotFoundException
+	if errors.As(err, &notFound) {
+		return false, nil
 	}
-	return false, nil
+	return err == nil, err
 }
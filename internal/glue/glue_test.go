@@ -0,0 +1,159 @@
+package glue
+
+import (
+	"slices"
+	"testing"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+)
+
+func TestClearRemovedFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *types.JobUpdate
+		live   *types.Job
+		check  func(*types.JobUpdate) bool
+	}{
+		{
+			name:   "new job",
+			update: &types.JobUpdate{},
+			live:   nil,
+			check: func(u *types.JobUpdate) bool {
+				return u.Description == nil && u.LogUri == nil && u.Connections == nil
+			},
+		},
+		{
+			name:   "fields removed from spec are cleared",
+			update: &types.JobUpdate{},
+			live: &types.Job{
+				Description:             aws.String("old"),
+				LogUri:                  aws.String("s3://logs/"),
+				Connections:             &types.ConnectionsList{Connections: []string{"vpc"}},
+				SecurityConfiguration:   aws.String("kms"),
+				NotificationProperty:    &types.NotificationProperty{NotifyDelayAfter: aws.Int32(5)},
+				NonOverridableArguments: map[string]string{"--a": "1"},
+				DefaultArguments:        map[string]string{"--b": "2"},
+				MaintenanceWindow:       aws.String("Sun:1"),
+			},
+			check: func(u *types.JobUpdate) bool {
+				return aws.ToString(u.Description) == "" && u.Description != nil &&
+					u.LogUri != nil && aws.ToString(u.LogUri) == "" &&
+					u.Connections != nil && len(u.Connections.Connections) == 0 &&
+					u.SecurityConfiguration != nil && aws.ToString(u.SecurityConfiguration) == "" &&
+					u.NotificationProperty != nil && u.NotificationProperty.NotifyDelayAfter == nil &&
+					u.NonOverridableArguments != nil && len(u.NonOverridableArguments) == 0 &&
+					u.DefaultArguments != nil && len(u.DefaultArguments) == 0 &&
+					u.MaintenanceWindow != nil && aws.ToString(u.MaintenanceWindow) == ""
+			},
+		},
+		{
+			name: "fields in spec are kept",
+			update: &types.JobUpdate{
+				Description:      aws.String("new"),
+				DefaultArguments: map[string]string{"--b": "3"},
+			},
+			live: &types.Job{
+				Description:      aws.String("old"),
+				DefaultArguments: map[string]string{"--b": "2"},
+			},
+			check: func(u *types.JobUpdate) bool {
+				return aws.ToString(u.Description) == "new" && u.DefaultArguments["--b"] == "3"
+			},
+		},
+		{
+			name:   "fields unset on AWS are left unset",
+			update: &types.JobUpdate{},
+			live:   &types.Job{Connections: &types.ConnectionsList{}},
+			check: func(u *types.JobUpdate) bool {
+				return u.Description == nil && u.Connections == nil && u.NotificationProperty == nil &&
+					u.DefaultArguments == nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearRemovedFields(tt.update, tt.live)
+			if !tt.check(tt.update) {
+				t.Errorf("unexpected update %+v", tt.update)
+			}
+		})
+	}
+}
+
+func TestTagsToAddAndRemove(t *testing.T) {
+	owner := jobOwner{Namespace: "team-a", Name: "etl", UID: "uid-1"}
+	ownership := map[string]string{
+		OwnerTagKey:     "true",
+		ClusterIDTagKey: "prod",
+		NamespaceTagKey: owner.Namespace,
+		NameTagKey:      owner.Name,
+		UIDTagKey:       owner.UID,
+	}
+	withOwnership := func(tags map[string]string) map[string]string {
+		all := map[string]string{}
+		for key, value := range ownership {
+			all[key] = value
+		}
+		for key, value := range tags {
+			all[key] = value
+		}
+		return all
+	}
+	tests := []struct {
+		name     string
+		spec     map[string]string
+		live     map[string]string
+		ignored  []string
+		toAdd    []string
+		toRemove []string
+	}{
+		{
+			name:     "in sync",
+			spec:     map[string]string{"team": "a"},
+			live:     withOwnership(map[string]string{"team": "a"}),
+			toAdd:    []string{},
+			toRemove: []string{},
+		},
+		{
+			name:     "changed and added tags",
+			spec:     map[string]string{"team": "b", "env": "prod"},
+			live:     withOwnership(map[string]string{"team": "a"}),
+			toAdd:    []string{"env", "team"},
+			toRemove: []string{},
+		},
+		{
+			name:     "removed tags",
+			spec:     map[string]string{},
+			live:     withOwnership(map[string]string{"team": "a", "cost": "1"}),
+			toAdd:    []string{},
+			toRemove: []string{"cost", "team"},
+		},
+		{
+			name:     "AWS, ignored and missing ownership tags",
+			spec:     map[string]string{},
+			live:     map[string]string{"aws:cloudformation:stack": "s", "backup-plan": "daily", OwnerTagKey: "true"},
+			ignored:  []string{"backup-"},
+			toAdd:    []string{ClusterIDTagKey, NameTagKey, NamespaceTagKey, UIDTagKey},
+			toRemove: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{
+				job:      awsv1alpha1.GlueJobSpec{Tags: tt.spec},
+				owner:    owner,
+				config:   config.OperatorConfig{ClusterID: "prod", IgnoredTagPrefixes: tt.ignored},
+				liveTags: tt.live,
+			}
+			if toAdd := job.tagsToAdd(); !slices.Equal(toAdd, tt.toAdd) {
+				t.Errorf("expected tags to add %v, got %v", tt.toAdd, toAdd)
+			}
+			if toRemove := job.tagsToRemove(); !slices.Equal(toRemove, tt.toRemove) {
+				t.Errorf("expected tags to remove %v, got %v", tt.toRemove, toRemove)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package glue
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// ModulesFileName is the file name of the bundle of Python modules
+	ModulesFileName = "modules.zip"
+	// extraPyFilesArg is the default argument with S3 paths of Python modules
+	extraPyFilesArg = "--extra-py-files"
+)
+
+// Limits of staged Python modules, they are bundled in memory of the operator
+var (
+	// maxStagedObjects is the maximum number of objects under S3 prefix of staged modules
+	maxStagedObjects = 1000
+	// maxStagedSize is the maximum total size of objects under S3 prefix of staged modules
+	maxStagedSize int64 = 64 << 20
+)
+
+// modulesModified is the modification time of files in the bundle, it's fixed to make the bundle deterministic
+var modulesModified = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ModuleFiles are the files of Python modules bundle by path in the bundle
+type ModuleFiles map[string][]byte
+
+// Add will add file to the bundle under directory dir, paths outside of the bundle and duplicates are rejected
+func (f ModuleFiles) Add(dir, name string, content []byte) error {
+	filePath := path.Clean(path.Join("/", dir, name))[1:]
+	if filePath == "" || filePath != path.Join(dir, name) {
+		return fmt.Errorf("invalid module path %s", path.Join(dir, name))
+	}
+	if _, ok := f[filePath]; ok {
+		return fmt.Errorf("duplicate module path %s", filePath)
+	}
+	f[filePath] = content
+	return nil
+}
+
+// Bundle will return zip file with the files, the same files always result in the same zip file,
+// so the bundle can be content addressed
+func (f ModuleFiles) Bundle() ([]byte, error) {
+	paths := make([]string, 0, len(f))
+	for filePath := range f {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, filePath := range paths {
+		header := &zip.FileHeader{
+			Name:     filePath,
+			Method:   zip.Deflate,
+			Modified: modulesModified,
+		}
+		header.SetMode(0o644)
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to modules bundle: %w", filePath, err)
+		}
+		_, err = w.Write(f[filePath])
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to modules bundle: %w", filePath, err)
+		}
+	}
+	err := zw.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write modules bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// StagedModules are the objects under S3 prefix of staged Python modules
+type StagedModules struct {
+	// Bucket is the bucket of the prefix
+	Bucket string
+	// prefix is the key prefix ending with slash, unless it's empty
+	prefix  string
+	objects []s3types.Object
+}
+
+// ListStaged will list objects under S3 prefix of staged Python modules, their number and total size are limited
+func ListStaged(ctx context.Context, client S3API, prefix string) (*StagedModules, error) {
+	bucket, keyPrefix, _ := strings.Cut(strings.TrimPrefix(prefix, s3Scheme), "/")
+	if !strings.HasPrefix(prefix, s3Scheme) || bucket == "" {
+		return nil, fmt.Errorf("invalid S3 prefix %s", prefix)
+	}
+	if keyPrefix != "" && !strings.HasSuffix(keyPrefix, "/") {
+		keyPrefix += "/"
+	}
+	staged := &StagedModules{Bucket: bucket, prefix: keyPrefix}
+	var size int64
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(keyPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list modules in %s: %w", prefix, err)
+		}
+		for _, object := range page.Contents {
+			// directory markers are skipped
+			if strings.HasSuffix(aws.ToString(object.Key), "/") {
+				continue
+			}
+			size += aws.ToInt64(object.Size)
+			if len(staged.objects) == maxStagedObjects || size > maxStagedSize {
+				return nil, fmt.Errorf("modules in %s exceed %d objects or %d bytes", prefix,
+					maxStagedObjects, maxStagedSize)
+			}
+			staged.objects = append(staged.objects, object)
+		}
+	}
+	return staged, nil
+}
+
+// Hash will return the hash of files and of keys and ETags of staged objects, it changes with the content
+// of the bundle, so the staged objects are only read, when it changes
+func (f ModuleFiles) Hash(staged *StagedModules) string {
+	hash := sha256.New()
+	paths := make([]string, 0, len(f))
+	for filePath := range f {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		sum := sha256.Sum256(f[filePath])
+		fmt.Fprintf(hash, "%s\x00%x\n", filePath, sum)
+	}
+	if staged != nil {
+		fmt.Fprintf(hash, "s3://%s/%s\n", staged.Bucket, staged.prefix)
+		for _, object := range staged.objects {
+			fmt.Fprintf(hash, "%s\x00%s\n", aws.ToString(object.Key), aws.ToString(object.ETag))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// AddStaged will add the staged objects to the bundle with paths relative to the prefix
+func (f ModuleFiles) AddStaged(ctx context.Context, client S3API, staged *StagedModules) error {
+	for _, object := range staged.objects {
+		key := aws.ToString(object.Key)
+		content, err := getObject(ctx, client, staged.Bucket, key, "", maxStagedSize)
+		if err != nil {
+			return err
+		}
+		err = f.Add("", strings.TrimPrefix(key, staged.prefix), content)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getObject will return the content of the version of S3 object, the latest version if versionID is empty.
+// Objects larger than maxSize are rejected
+func getObject(ctx context.Context, client S3API, bucket, key, versionID string, maxSize int64) ([]byte, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+	out, err := client.GetObject(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get s3://%s/%s: %w", bucket, key, err)
+	}
+	defer out.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(out.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", bucket, key, err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("s3://%s/%s is larger than %d bytes", bucket, key, maxSize)
+	}
+	return content, nil
+}
+
+// WithModules will return default arguments with the bundle of Python modules appended to --extra-py-files
+func WithModules(args map[string]string, location string) map[string]string {
+	withModules := make(map[string]string, len(args)+1)
+	for k, v := range args {
+		withModules[k] = v
+	}
+	if extra := strings.TrimSpace(args[extraPyFilesArg]); extra != "" {
+		withModules[extraPyFilesArg] = extra + "," + location
+	} else {
+		withModules[extraPyFilesArg] = location
+	}
+	return withModules
+}
+
+// SetModules will set the bundle of Python modules, which is uploaded to location by UploadModules,
+// and the hash of its sources. Nil bundle means the bundle of the same sources is uploaded already
+func (g *Job) SetModules(location string, bundle []byte, hash string) {
+	g.modulesLocation = location
+	g.modules = bundle
+	g.modulesHash = hash
+}
+
+// ModulesHash will return the hash of sources of the bundle of Python modules
+func (g *Job) ModulesHash() string {
+	return g.modulesHash
+}
+
+// UploadedModules will return the S3 path of the bundle of Python modules, if it's uploaded by the operator
+func (g *Job) UploadedModules() string {
+	return g.modulesLocation
+}
+
+// UploadModules will upload the bundle of Python modules set by SetModules, unless it's already there.
+// It returns true, if the bundle was uploaded
+func (g *Job) UploadModules() (bool, error) {
+	if g.modules == nil {
+		return false, nil
+	}
+	return g.upload(g.modulesLocation, g.modules)
+}
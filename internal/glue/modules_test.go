@@ -0,0 +1,204 @@
+package glue
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestModulesBundleDeterministic(t *testing.T) {
+	files := ModuleFiles{}
+	for _, name := range []string{"utils.py", "__init__.py", "io.py"} {
+		if err := files.Add("mypkg", name, []byte("# "+name)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	first, err := files.Bundle()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := files.Bundle()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("expected the same bundle of the same files")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatalf("invalid bundle: %v", err)
+	}
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	expected := []string{"mypkg/__init__.py", "mypkg/io.py", "mypkg/utils.py"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected files %v, got %v", expected, names)
+	}
+	rc, err := zr.File[2].Open()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rc.Close()
+	content, _ := io.ReadAll(rc)
+	if string(content) != "# utils.py" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
+
+func TestModuleFilesAdd(t *testing.T) {
+	files := ModuleFiles{}
+	if err := files.Add("", "main.py", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := files.Add("", "main.py", nil); err == nil {
+		t.Error("expected error of duplicate path")
+	}
+	for _, dir := range []string{"../pkg", "/pkg", "pkg/../.."} {
+		if err := files.Add(dir, "a.py", nil); err == nil {
+			t.Errorf("expected error of path %s/a.py outside of the bundle", dir)
+		}
+	}
+}
+
+func TestWithModules(t *testing.T) {
+	args := map[string]string{"--extra-py-files": "s3://bucket/a.py"}
+	withModules := WithModules(args, "s3://bucket/modules.zip")
+	if withModules[extraPyFilesArg] != "s3://bucket/a.py,s3://bucket/modules.zip" {
+		t.Errorf("unexpected --extra-py-files %s", withModules[extraPyFilesArg])
+	}
+	if args[extraPyFilesArg] != "s3://bucket/a.py" {
+		t.Error("expected default arguments not to be modified")
+	}
+	withModules = WithModules(nil, "s3://bucket/modules.zip")
+	if withModules[extraPyFilesArg] != "s3://bucket/modules.zip" {
+		t.Errorf("unexpected --extra-py-files %s", withModules[extraPyFilesArg])
+	}
+}
+
+// fakeS3 serves objects by key from a single bucket, other calls of S3API aren't implemented
+type fakeS3 struct {
+	S3API
+	objects map[string]string
+	gets    int
+}
+
+func (f *fakeS3) ListObjectsV2(_ context.Context, params *s3.ListObjectsV2Input,
+	_ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	keys := make([]string, 0, len(f.objects))
+	for key := range f.objects {
+		if strings.HasPrefix(key, aws.ToString(params.Prefix)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	out := &s3.ListObjectsV2Output{}
+	for _, key := range keys {
+		sum := sha256.Sum256([]byte(f.objects[key]))
+		out.Contents = append(out.Contents, s3types.Object{
+			Key:  aws.String(key),
+			ETag: aws.String(hex.EncodeToString(sum[:])),
+			Size: aws.Int64(int64(len(f.objects[key]))),
+		})
+	}
+	return out, nil
+}
+
+func (f *fakeS3) GetObject(_ context.Context, params *s3.GetObjectInput,
+	_ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.gets++
+	content, ok := f.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, &s3types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(content))}, nil
+}
+
+func TestStagedModules(t *testing.T) {
+	client := &fakeS3{objects: map[string]string{
+		"staged/mypkg/__init__.py": "",
+		"staged/mypkg/utils.py":    "X = 1",
+		"staged/":                  "",
+		"other/secret.txt":         "secret",
+	}}
+	ctx := context.Background()
+	staged, err := ListStaged(ctx, client, "s3://modules/staged")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if staged.Bucket != "modules" || len(staged.objects) != 2 {
+		t.Errorf("expected 2 objects in bucket modules, got %d in %s", len(staged.objects), staged.Bucket)
+	}
+	files := ModuleFiles{}
+	if err = files.AddStaged(ctx, client, staged); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(files) != 2 || string(files["mypkg/utils.py"]) != "X = 1" {
+		t.Errorf("unexpected files %v", files)
+	}
+
+	hash := (ModuleFiles{}).Hash(staged)
+	client.objects["staged/mypkg/utils.py"] = "X = 2"
+	changed, err := ListStaged(ctx, client, "s3://modules/staged")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if (ModuleFiles{}).Hash(changed) == hash {
+		t.Error("expected hash to change with ETag of staged object")
+	}
+	if _, err = ListStaged(ctx, client, "modules/staged"); err == nil {
+		t.Error("expected error of prefix without s3://")
+	}
+}
+
+func TestStagedModulesLimits(t *testing.T) {
+	client := &fakeS3{objects: map[string]string{"staged/a.py": "1234", "staged/b.py": "5678"}}
+	ctx := context.Background()
+	defer func(objects int, size int64) { maxStagedObjects, maxStagedSize = objects, size }(maxStagedObjects, maxStagedSize)
+
+	maxStagedObjects = 1
+	if _, err := ListStaged(ctx, client, "s3://modules/staged"); err == nil {
+		t.Error("expected error of too many objects")
+	}
+	maxStagedObjects, maxStagedSize = 10, 6
+	if _, err := ListStaged(ctx, client, "s3://modules/staged"); err == nil {
+		t.Error("expected error of too large objects")
+	}
+	// listed size may be stale, so the read is limited too
+	maxStagedSize = 8
+	staged, err := ListStaged(ctx, client, "s3://modules/staged")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client.objects["staged/a.py"] = "123456789"
+	if err = (ModuleFiles{}).AddStaged(ctx, client, staged); err == nil {
+		t.Error("expected error of object larger than the limit")
+	}
+}
+
+func TestModuleFilesHash(t *testing.T) {
+	files := ModuleFiles{"mypkg/utils.py": []byte("X = 1")}
+	hash := files.Hash(nil)
+	if files.Hash(nil) != hash {
+		t.Error("expected stable hash")
+	}
+	if (ModuleFiles{"mypkg/utils.py": []byte("X = 2")}).Hash(nil) == hash {
+		t.Error("expected hash to change with content")
+	}
+	if (ModuleFiles{"other/utils.py": []byte("X = 1")}).Hash(nil) == hash {
+		t.Error("expected hash to change with path")
+	}
+}
@@ -0,0 +1,83 @@
+package glue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsglue "github.com/aws/aws-sdk-go-v2/service/glue"
+)
+
+// OwnedJob is a Glue Job on AWS owned by the operator in this cluster
+type OwnedJob struct {
+	// Name is the name of the Glue Job
+	Name string
+	// Namespace, OwnerName and UID identify GlueJob, which owns the Glue Job.
+	// They are empty for jobs created by older versions of the operator
+	Namespace string
+	OwnerName string
+	UID       string
+}
+
+// OwnedJobs lists and deletes Glue Jobs owned by the operator in this cluster
+type OwnedJobs struct {
+	ctx       context.Context
+	config    config.OperatorConfig
+	awsClient *awsglue.Client
+	accountID string
+	region    string
+}
+
+// NewOwnedJobs will return a new OwnedJobs struct for account and region of AWS clients
+func NewOwnedJobs(ctx context.Context, cfg config.OperatorConfig, clients *awsclient.Clients) *OwnedJobs {
+	return &OwnedJobs{
+		ctx:       ctx,
+		config:    cfg,
+		awsClient: clients.Glue,
+		accountID: clients.AccountID,
+		region:    clients.Region,
+	}
+}
+
+// List will return all Glue Jobs owned by the operator in this cluster
+func (o *OwnedJobs) List() ([]OwnedJob, error) {
+	jobsPaginator := awsglue.NewListJobsPaginator(o.awsClient, &awsglue.ListJobsInput{
+		MaxResults: aws.Int32(100),
+		Tags:       map[string]string{OwnerTagKey: "true", ClusterIDTagKey: o.config.ClusterID},
+	})
+	jobs := make([]OwnedJob, 0)
+	for jobsPaginator.HasMorePages() {
+		jobsOut, err := jobsPaginator.NextPage(o.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get next page from Jobs paginator: %w", err)
+		}
+		for _, jobName := range jobsOut.JobNames {
+			tagsOut, err := o.awsClient.GetTags(o.ctx, &awsglue.GetTagsInput{
+				ResourceArn: aws.String(jobARN(o.region, o.accountID, jobName)),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get Glue Job tags %s: %w", jobName, err)
+			}
+			jobs = append(jobs, OwnedJob{
+				Name:      jobName,
+				Namespace: tagsOut.Tags[NamespaceTagKey],
+				OwnerName: tagsOut.Tags[NameTagKey],
+				UID:       tagsOut.Tags[UIDTagKey],
+			})
+		}
+	}
+	return jobs, nil
+}
+
+// Delete will delete Glue Job owned by the operator
+func (o *OwnedJobs) Delete(name string) error {
+	_, err := o.awsClient.DeleteJob(o.ctx, &awsglue.DeleteJobInput{
+		JobName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete Glue Job %s: %w", name, err)
+	}
+	return nil
+}
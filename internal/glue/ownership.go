@@ -0,0 +1,83 @@
+package glue
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// OwnerTagKey is the tag, which marks Glue Job as owned by the operator
+	OwnerTagKey = "glue-jobs-operator"
+	// ClusterIDTagKey is the tag with ID of the cluster, which operator owns the Glue Job
+	ClusterIDTagKey = OwnerTagKey + "/cluster-id"
+	// NamespaceTagKey is the tag with namespace of GlueJob, which owns the Glue Job
+	NamespaceTagKey = OwnerTagKey + "/namespace"
+	// NameTagKey is the tag with name of GlueJob, which owns the Glue Job
+	NameTagKey = OwnerTagKey + "/name"
+	// UIDTagKey is the tag with UID of GlueJob, which owns the Glue Job
+	UIDTagKey = OwnerTagKey + "/uid"
+)
+
+// jobOwner identifies GlueJob, which owns the Glue Job on AWS
+type jobOwner struct {
+	Namespace string
+	Name      string
+	UID       string
+}
+
+// OwnershipConflictError is returned when Glue Job on AWS is owned by
+// another cluster or by another GlueJob
+type OwnershipConflictError struct {
+	JobName string
+	Owner   string
+}
+
+func (e *OwnershipConflictError) Error() string {
+	return fmt.Sprintf("Glue Job %s is owned by %s", e.JobName, e.Owner)
+}
+
+// isOwnershipTag will return true if tag is one of the operator ownership tags
+func isOwnershipTag(key string) bool {
+	return key == OwnerTagKey || strings.HasPrefix(key, OwnerTagKey+"/")
+}
+
+// ownershipTags will return tags, which mark the Glue Job as owned by this cluster and GlueJob
+func (g *Job) ownershipTags() map[string]string {
+	return map[string]string{
+		OwnerTagKey:     "true",
+		ClusterIDTagKey: g.config.ClusterID,
+		NamespaceTagKey: g.owner.Namespace,
+		NameTagKey:      g.owner.Name,
+		UIDTagKey:       g.owner.UID,
+	}
+}
+
+// checkOwnership will return OwnershipConflictError if Glue Job tags show, that
+// the job is owned by another cluster or by another GlueJob in this cluster.
+// Jobs without owner tags (e.g. created by older versions of the operator) are adopted,
+// but jobs with owner tags and without cluster tag may be owned by any cluster and are never adopted.
+func (g *Job) checkOwnership(tags map[string]string) error {
+	namespace, name := tags[NamespaceTagKey], tags[NameTagKey]
+	clusterID, ok := tags[ClusterIDTagKey]
+	switch {
+	case ok && clusterID != g.config.ClusterID:
+		return &OwnershipConflictError{
+			JobName: g.job.Name,
+			Owner:   fmt.Sprintf("cluster %q", clusterID),
+		}
+	case namespace == "" && name == "":
+		return nil
+	case !ok:
+		return &OwnershipConflictError{
+			JobName: g.job.Name,
+			Owner:   fmt.Sprintf("GlueJob %s/%s of unknown cluster", namespace, name),
+		}
+	}
+	if namespace != g.owner.Namespace || name != g.owner.Name {
+		return &OwnershipConflictError{
+			JobName: g.job.Name,
+			Owner:   fmt.Sprintf("GlueJob %s/%s", namespace, name),
+		}
+	}
+	return nil
+}
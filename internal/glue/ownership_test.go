@@ -0,0 +1,63 @@
+package glue
+
+import (
+	"errors"
+	"testing"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+func TestCheckOwnership(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		conflict bool
+	}{
+		{
+			name: "not owned",
+			tags: map[string]string{"team": "a"},
+		},
+		{
+			name: "owned by this GlueJob",
+			tags: map[string]string{
+				OwnerTagKey: "true", ClusterIDTagKey: "prod", NamespaceTagKey: "team-a", NameTagKey: "etl",
+			},
+		},
+		{
+			name: "tagged with cluster only",
+			tags: map[string]string{OwnerTagKey: "true", ClusterIDTagKey: "prod"},
+		},
+		{
+			name:     "owned by another cluster",
+			tags:     map[string]string{OwnerTagKey: "true", ClusterIDTagKey: "dev"},
+			conflict: true,
+		},
+		{
+			name: "owned by another GlueJob",
+			tags: map[string]string{
+				OwnerTagKey: "true", ClusterIDTagKey: "prod", NamespaceTagKey: "team-b", NameTagKey: "etl",
+			},
+			conflict: true,
+		},
+		{
+			name:     "owned by GlueJob of unknown cluster",
+			tags:     map[string]string{OwnerTagKey: "true", NamespaceTagKey: "team-a", NameTagKey: "etl"},
+			conflict: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{
+				job:    awsv1alpha1.GlueJobSpec{Name: "etl"},
+				owner:  jobOwner{Namespace: "team-a", Name: "etl", UID: "uid-1"},
+				config: config.OperatorConfig{ClusterID: "prod"},
+			}
+			err := job.checkOwnership(tt.tags)
+			var conflict *OwnershipConflictError
+			if errors.As(err, &conflict) != tt.conflict {
+				t.Errorf("expected conflict %v, got %v", tt.conflict, err)
+			}
+		})
+	}
+}
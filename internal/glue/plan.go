@@ -0,0 +1,148 @@
+package glue
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+)
+
+// Planned mutations of Glue Job on AWS
+const (
+	PlanCreateJob     = "CreateJob"
+	PlanUpdateJob     = "UpdateJob"
+	PlanTagResource   = "TagResource"
+	PlanUntagResource = "UntagResource"
+	PlanDeleteJob     = "DeleteJob"
+)
+
+// Plan will return mutations, which CreateJob or UpateJob would make on AWS, e.g.
+// "UpdateJob: command.scriptLocation, role" or "TagResource: team". Only names of fields
+// and keys of arguments and tags are reported, so no values leak to status or events
+func (g *Job) Plan() []string {
+	if !g.exists {
+		return []string{PlanCreateJob}
+	}
+	plan := make([]string, 0)
+	update := g.jobUpdate()
+	clearRemovedFields(update, g.live)
+	if fields := changedFields(update, g.live); len(fields) > 0 {
+		plan = append(plan, planEntry(PlanUpdateJob, fields))
+	}
+	if keys := g.tagsToAdd(); len(keys) > 0 {
+		plan = append(plan, planEntry(PlanTagResource, keys))
+	}
+	if keys := g.tagsToRemove(); len(keys) > 0 {
+		plan = append(plan, planEntry(PlanUntagResource, keys))
+	}
+	return plan
+}
+
+// DesiredState will return the definition and tags, which CreateJob or UpateJob would set on AWS.
+// Unlike Plan it has values, it must not be written to status or events
+func (g *Job) DesiredState() ([]byte, error) {
+	update := g.jobUpdate()
+	clearRemovedFields(update, g.live)
+	return json.Marshal(struct {
+		Job  *types.JobUpdate
+		Tags map[string]string
+	}{update, g.getTags()})
+}
+
+// DeletePlan will return mutations, which DeleteJob would make on AWS
+func (g *Job) DeletePlan() []string {
+	if !g.exists {
+		return []string{}
+	}
+	return []string{PlanDeleteJob}
+}
+
+// planEntry will return planned mutation with the list of affected fields or keys
+func planEntry(mutation string, items []string) string {
+	return fmt.Sprintf("%s: %s", mutation, strings.Join(items, ", "))
+}
+
+// tagsToAdd will return sorted keys of tags, which are missing on Glue Job or have another value
+func (g *Job) tagsToAdd() []string {
+	keys := make([]string, 0)
+	for key, value := range g.getTags() {
+		if live, ok := g.liveTags[key]; !ok || live != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tagsToRemove will return sorted keys of tags, which were removed from the spec
+func (g *Job) tagsToRemove() []string {
+	tags := g.getTags()
+	keys := make([]string, 0)
+	for key := range g.liveTags {
+		if _, ok := tags[key]; ok || g.isIgnoredTag(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// changedFields will return names of fields, which update would change on live Glue Job
+func changedFields(update *types.JobUpdate, live *types.Job) []string {
+	fields := make([]string, 0)
+	changed := func(name string, differ bool) {
+		if differ {
+			fields = append(fields, name)
+		}
+	}
+	liveCommand := live.Command
+	if liveCommand == nil {
+		liveCommand = &types.JobCommand{}
+	}
+	changed("command.name", aws.ToString(update.Command.Name) != aws.ToString(liveCommand.Name))
+	changed("command.pythonVersion", aws.ToString(update.Command.PythonVersion) != aws.ToString(liveCommand.PythonVersion))
+	changed("command.scriptLocation", aws.ToString(update.Command.ScriptLocation) != aws.ToString(liveCommand.ScriptLocation))
+	changed("command.runtime", update.Command.Runtime != nil &&
+		aws.ToString(update.Command.Runtime) != aws.ToString(liveCommand.Runtime))
+	changed("role", aws.ToString(update.Role) != aws.ToString(live.Role))
+	changed("timeout", aws.ToInt32(update.Timeout) != aws.ToInt32(live.Timeout))
+	changed("glueVersion", aws.ToString(update.GlueVersion) != aws.ToString(live.GlueVersion))
+	changed("numberOfWorkers", aws.ToInt32(update.NumberOfWorkers) != aws.ToInt32(live.NumberOfWorkers))
+	changed("workerType", update.WorkerType != "" && update.WorkerType != live.WorkerType)
+	changed("executionClass", update.ExecutionClass != "" && update.ExecutionClass != live.ExecutionClass)
+	liveConcurrentRuns := int32(0)
+	if live.ExecutionProperty != nil {
+		liveConcurrentRuns = live.ExecutionProperty.MaxConcurrentRuns
+	}
+	changed("executionProperty", update.ExecutionProperty.MaxConcurrentRuns != liveConcurrentRuns)
+	changed("maxRetries", update.MaxRetries != live.MaxRetries)
+	changed("defaultArguments", update.DefaultArguments != nil && !maps.Equal(update.DefaultArguments, live.DefaultArguments))
+	changed("description", update.Description != nil && aws.ToString(update.Description) != aws.ToString(live.Description))
+	changed("logUri", update.LogUri != nil && aws.ToString(update.LogUri) != aws.ToString(live.LogUri))
+	liveConnections := []string{}
+	if live.Connections != nil {
+		liveConnections = live.Connections.Connections
+	}
+	changed("connections", update.Connections != nil && !slices.Equal(update.Connections.Connections, liveConnections))
+	changed("securityConfiguration", update.SecurityConfiguration != nil &&
+		aws.ToString(update.SecurityConfiguration) != aws.ToString(live.SecurityConfiguration))
+	liveNotifyDelay := int32(0)
+	if live.NotificationProperty != nil {
+		liveNotifyDelay = aws.ToInt32(live.NotificationProperty.NotifyDelayAfter)
+	}
+	changed("notificationProperty", update.NotificationProperty != nil &&
+		aws.ToInt32(update.NotificationProperty.NotifyDelayAfter) != liveNotifyDelay)
+	changed("nonOverridableArguments", update.NonOverridableArguments != nil &&
+		!maps.Equal(update.NonOverridableArguments, live.NonOverridableArguments))
+	changed("maintenanceWindow", update.MaintenanceWindow != nil &&
+		aws.ToString(update.MaintenanceWindow) != aws.ToString(live.MaintenanceWindow))
+	changed("jobMode", update.JobMode != "" && update.JobMode != live.JobMode)
+	changed("sourceControlDetails", sourceControlChanged(update.SourceControlDetails, live.SourceControlDetails))
+	return fields
+}
@@ -0,0 +1,120 @@
+package glue
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+// newPlanJob will return Job of spec, which live Glue Job and tags match spec
+func newPlanJob(spec awsv1alpha1.GlueJobSpec) *Job {
+	job := &Job{
+		job:    withDefaults(spec),
+		owner:  jobOwner{Namespace: "team-a", Name: "etl", UID: "uid-1"},
+		config: config.OperatorConfig{ClusterID: "prod"},
+		exists: true,
+	}
+	update := job.jobUpdate()
+	job.live = &types.Job{
+		Command:                 update.Command,
+		Role:                    update.Role,
+		Timeout:                 update.Timeout,
+		GlueVersion:             update.GlueVersion,
+		NumberOfWorkers:         update.NumberOfWorkers,
+		WorkerType:              update.WorkerType,
+		ExecutionClass:          update.ExecutionClass,
+		ExecutionProperty:       update.ExecutionProperty,
+		MaxRetries:              update.MaxRetries,
+		DefaultArguments:        update.DefaultArguments,
+		NonOverridableArguments: update.NonOverridableArguments,
+		Description:             update.Description,
+	}
+	job.liveTags = job.getTags()
+	return job
+}
+
+func testPlanSpec() awsv1alpha1.GlueJobSpec {
+	return awsv1alpha1.GlueJobSpec{
+		Name:             "prod_team-a_etl",
+		Description:      "ETL",
+		Role:             "arn:aws:iam::123456789012:role/glue",
+		Command:          awsv1alpha1.GlueJobCommand{Name: "glueetl", ScriptLocation: "s3://scripts/etl.py"},
+		DefaultArguments: map[string]string{"--ENV": "dev"},
+		Tags:             map[string]string{"team": "a"},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*Job)
+		expected []string
+	}{
+		{name: "in sync", modify: func(*Job) {}, expected: []string{}},
+		{
+			name:     "missing job",
+			modify:   func(j *Job) { j.exists, j.live, j.liveTags = false, nil, nil },
+			expected: []string{PlanCreateJob},
+		},
+		{
+			name: "changed fields and argument values",
+			modify: func(j *Job) {
+				j.job.Role = "arn:aws:iam::123456789012:role/other"
+				j.job.DefaultArguments = map[string]string{"--ENV": "prod"}
+			},
+			expected: []string{"UpdateJob: role, defaultArguments"},
+		},
+		{
+			name:     "removed description",
+			modify:   func(j *Job) { j.job.Description = "" },
+			expected: []string{"UpdateJob: description"},
+		},
+		{
+			name: "changed tags",
+			modify: func(j *Job) {
+				j.job.Tags = map[string]string{"cost": "1"}
+			},
+			expected: []string{"TagResource: cost", "UntagResource: team"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newPlanJob(testPlanSpec())
+			tt.modify(job)
+			if plan := job.Plan(); !slices.Equal(plan, tt.expected) {
+				t.Errorf("expected plan %v, got %v", tt.expected, plan)
+			}
+		})
+	}
+}
+
+func TestDesiredState(t *testing.T) {
+	job := newPlanJob(testPlanSpec())
+	desired, err := job.DesiredState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := newPlanJob(testPlanSpec()).DesiredState()
+	if err != nil || !bytes.Equal(desired, again) {
+		t.Errorf("expected stable desired state, got %s and %s: %v", desired, again, err)
+	}
+
+	// values, which Plan doesn't report, change the desired state
+	for name, modify := range map[string]func(*Job){
+		"argument value":  func(j *Job) { j.job.DefaultArguments["--ENV"] = "prod" },
+		"script location": func(j *Job) { j.job.Command.ScriptLocation = "s3://scripts/etl-abc.py" },
+		"tag value":       func(j *Job) { j.job.Tags["team"] = "b" },
+	} {
+		changed := newPlanJob(testPlanSpec())
+		modify(changed)
+		state, err := changed.DesiredState()
+		if err != nil || bytes.Equal(desired, state) {
+			t.Errorf("expected desired state to change with %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package glue
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ScriptObject will return content addressed S3 path of script in bucket under prefix,
+// scripts of Glue Job with different content get different paths, the file name is kept
+func ScriptObject(bucket, prefix, jobName, fileName string, script []byte) string {
+	sum := sha256.Sum256(script)
+	return s3Scheme + bucket + "/" + path.Join(prefix, jobName, hex.EncodeToString(sum[:8]), fileName)
+}
+
+// ScriptVersion is the version of the script object in S3
+type ScriptVersion struct {
+	// ETag is the entity tag of the object, it changes with the content
+	ETag string
+	// VersionID is the version of the object, it's empty in unversioned buckets
+	VersionID string
+}
+
+// maxScriptSize is the maximum size of pinned script version, it's copied in memory of the operator
+var maxScriptSize int64 = 16 << 20
+
+// PinnedScript will return the content and the file name of the version of script in S3
+func PinnedScript(ctx context.Context, client S3API, location, versionID string) ([]byte, string, error) {
+	bucket, key, err := parseS3URI(location)
+	if err != nil {
+		return nil, "", err
+	}
+	script, err := getObject(ctx, client, bucket, key, versionID, maxScriptSize)
+	if err != nil {
+		return nil, "", fmt.Errorf("version %s of script: %w", versionID, err)
+	}
+	return script, path.Base(key), nil
+}
+
+// PinnedScriptVersion will return the pinned version of script as <location>?versionId=<id>
+func PinnedScriptVersion(location, versionID string) string {
+	return location + "?versionId=" + versionID
+}
+
+// ScriptVersion will return the version of the script, which Glue Job runs
+func (g *Job) ScriptVersion() (ScriptVersion, error) {
+	location := g.job.Command.ScriptLocation
+	bucket, key, err := parseS3URI(location)
+	if err != nil {
+		return ScriptVersion{}, err
+	}
+	out, err := g.s3Client.HeadObject(g.ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ScriptVersion{}, fmt.Errorf("failed to get version of script %s: %w", location, err)
+	}
+	return ScriptVersion{
+		ETag:      strings.Trim(aws.ToString(out.ETag), `"`),
+		VersionID: aws.ToString(out.VersionId),
+	}, nil
+}
+
+// SetScript will set the script, which is uploaded to the script location of Glue Job by UploadScript,
+// and the commit of Git repository the script comes from, if any
+func (g *Job) SetScript(script []byte, commit string) {
+	g.script = script
+	g.scriptCommit = commit
+}
+
+// SetPinnedScript will set the pinned script version (see PinnedScriptVersion), which is copied to the script
+// location of Glue Job by UploadScript. Nil script means the version is copied already
+func (g *Job) SetPinnedScript(script []byte, pinned string) {
+	g.script = script
+	g.pinnedScript = pinned
+}
+
+// PinnedScript will return the pinned script version copied to the script location, empty if it isn't pinned
+func (g *Job) PinnedScript() string {
+	return g.pinnedScript
+}
+
+// ScriptCommit will return the commit of Git repository the script comes from
+func (g *Job) ScriptCommit() string {
+	return g.scriptCommit
+}
+
+// ScriptLocation will return the script location of Glue Job
+func (g *Job) ScriptLocation() string {
+	return g.job.Command.ScriptLocation
+}
+
+// UploadedScript will return the script location, if the script is uploaded by the operator
+func (g *Job) UploadedScript() string {
+	if g.script == nil && g.pinnedScript == "" {
+		return ""
+	}
+	return g.job.Command.ScriptLocation
+}
+
+// UploadScript will upload the script set by SetScript to the script location, unless it's already there.
+// It returns true, if the script was uploaded
+func (g *Job) UploadScript() (bool, error) {
+	if g.script == nil {
+		return false, nil
+	}
+	return g.upload(g.job.Command.ScriptLocation, g.script)
+}
+
+// upload will upload content to S3 path, unless it's already there. It returns true, if content was uploaded
+func (g *Job) upload(location string, content []byte) (bool, error) {
+	missing, err := MissingArtifacts(g.ctx, g.s3Client, []string{location})
+	if err != nil {
+		return false, err
+	}
+	if len(missing) == 0 {
+		return false, nil
+	}
+	bucket, key, err := parseS3URI(location)
+	if err != nil {
+		return false, err
+	}
+	_, err = g.s3Client.PutObject(g.ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to upload to %s: %w", location, err)
+	}
+	return true, nil
+}
+
+// DeleteUploaded will delete the object (e.g. script) uploaded by the operator to S3 path
+func (g *Job) DeleteUploaded(location string) error {
+	bucket, key, err := parseS3URI(location)
+	if err != nil {
+		return err
+	}
+	_, err = g.s3Client.DeleteObject(g.ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("failed to delete %s: %w", location, err)
+	}
+	return nil
+}
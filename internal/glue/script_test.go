@@ -0,0 +1,20 @@
+package glue
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPinnedScriptLimit(t *testing.T) {
+	client := &fakeS3{objects: map[string]string{"jobs/etl.py": "print('etl')"}}
+	ctx := context.Background()
+	script, fileName, err := PinnedScript(ctx, client, "s3://ci/jobs/etl.py", "v1")
+	if err != nil || string(script) != "print('etl')" || fileName != "etl.py" {
+		t.Errorf("unexpected script %s %q: %v", fileName, script, err)
+	}
+	defer func(size int64) { maxScriptSize = size }(maxScriptSize)
+	maxScriptSize = 4
+	if _, _, err = PinnedScript(ctx, client, "s3://ci/jobs/etl.py", "v1"); err == nil {
+		t.Error("expected error of script larger than the limit")
+	}
+}
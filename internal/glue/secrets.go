@@ -0,0 +1,198 @@
+package glue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	smtypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+)
+
+const (
+	// secretsManagerService is the service of Secrets Manager secret ARNs
+	secretsManagerService = "secretsmanager"
+	// ssmService is the service of SSM parameter ARNs
+	ssmService = "ssm"
+)
+
+// SecretsManagerAPI is the part of Secrets Manager client used to check, that secrets exist
+type SecretsManagerAPI interface {
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput,
+		optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+}
+
+// SSMAPI is the part of SSM client used to check, that parameters exist
+type SSMAPI interface {
+	DescribeParameters(ctx context.Context, params *ssm.DescribeParametersInput,
+		optFns ...func(*ssm.Options)) (*ssm.DescribeParametersOutput, error)
+}
+
+// IAMAPI is the part of IAM client used to check, that the role of Glue Job can read secrets
+type IAMAPI interface {
+	SimulatePrincipalPolicy(ctx context.Context, params *iam.SimulatePrincipalPolicyInput,
+		optFns ...func(*iam.Options)) (*iam.SimulatePrincipalPolicyOutput, error)
+}
+
+// SecretsClients are AWS clients used to validate secrets of Glue Job
+type SecretsClients struct {
+	SecretsManager SecretsManagerAPI
+	SSM            SSMAPI
+	IAM            IAMAPI
+}
+
+// secretArgument will return the default argument with the ARN of secret and the action reading it
+func secretArgument(secret awsv1alpha1.GlueJobAWSSecret) (string, string, error) {
+	parsed, err := arn.Parse(secret.ARN)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid ARN of secret %s: %w", secret.Name, err)
+	}
+	switch {
+	case parsed.Service == secretsManagerService && strings.HasPrefix(parsed.Resource, "secret:"):
+		return "--" + secret.Name + "-secret-arn", "secretsmanager:GetSecretValue", nil
+	case parsed.Service == ssmService && strings.HasPrefix(parsed.Resource, "parameter/"):
+		return "--" + secret.Name + "-parameter-arn", "ssm:GetParameter", nil
+	}
+	return "", "", fmt.Errorf("secret %s must be Secrets Manager secret or SSM parameter, got %s", secret.Name, secret.ARN)
+}
+
+// secretArguments will return default arguments with ARNs of secrets
+func secretArguments(secrets []awsv1alpha1.GlueJobAWSSecret) map[string]string {
+	args := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		arg, _, err := secretArgument(secret)
+		if err != nil {
+			// invalid secrets are rejected by validateSpec
+			continue
+		}
+		args[arg] = secret.ARN
+	}
+	return args
+}
+
+// validateSecrets will check, that ARNs of secrets are valid and their arguments aren't set in default arguments
+func validateSecrets(spec *awsv1alpha1.GlueJobSpec) error {
+	for _, secret := range spec.AWSSecrets {
+		arg, _, err := secretArgument(secret)
+		if err != nil {
+			return err
+		}
+		if _, ok := spec.DefaultArguments[arg]; ok {
+			return fmt.Errorf("argument %s must be set via awsSecrets, not via defaultArguments", arg)
+		}
+	}
+	return nil
+}
+
+// parameterName will return the name of SSM parameter from the resource of its ARN,
+// names of parameters in hierarchies start with slash
+func parameterName(resource string) string {
+	name := strings.TrimPrefix(resource, "parameter/")
+	if strings.Contains(name, "/") {
+		return "/" + name
+	}
+	return name
+}
+
+// roleARN will return ARN of the role of Glue Job, which can be set by name
+func roleARN(role, partition, accountID string) string {
+	if strings.HasPrefix(role, "arn:") {
+		return role
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, accountID, role)
+}
+
+// ValidateSecrets will check, that secrets exist and the role can read them. Region of the clients
+// is overridden by the region of each secret, the role is ARN or name of the role in account
+func ValidateSecrets(ctx context.Context, clients SecretsClients, role, accountID string,
+	secrets []awsv1alpha1.GlueJobAWSSecret) error {
+	errs := make([]error, 0)
+	for _, secret := range secrets {
+		err := validateSecret(ctx, clients, role, accountID, secret)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// validateSecret will check, that secret exists and the role can read it
+func validateSecret(ctx context.Context, clients SecretsClients, role, accountID string,
+	secret awsv1alpha1.GlueJobAWSSecret) error {
+	_, action, err := secretArgument(secret)
+	if err != nil {
+		return err
+	}
+	parsed, _ := arn.Parse(secret.ARN)
+	if parsed.Service == secretsManagerService {
+		_, err = clients.SecretsManager.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+			SecretId: aws.String(secret.ARN),
+		}, func(o *secretsmanager.Options) {
+			if parsed.Region != "" {
+				o.Region = parsed.Region
+			}
+		})
+		var notFound *smtypes.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return fmt.Errorf("secret %s doesn't exist: %s", secret.Name, secret.ARN)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to describe secret %s: %w", secret.Name, err)
+		}
+	} else {
+		output, err := clients.SSM.DescribeParameters(ctx, &ssm.DescribeParametersInput{
+			ParameterFilters: []ssmtypes.ParameterStringFilter{{
+				Key:    aws.String("Name"),
+				Option: aws.String("Equals"),
+				Values: []string{parameterName(parsed.Resource)},
+			}},
+		}, func(o *ssm.Options) {
+			if parsed.Region != "" {
+				o.Region = parsed.Region
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe parameter %s: %w", secret.Name, err)
+		}
+		if len(output.Parameters) == 0 {
+			return fmt.Errorf("parameter %s doesn't exist: %s", secret.Name, secret.ARN)
+		}
+	}
+
+	// only identity-based policies of the role are simulated, resource and KMS key policies may still deny access
+	output, err := clients.IAM.SimulatePrincipalPolicy(ctx, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(roleARN(role, parsed.Partition, accountID)),
+		ActionNames:     []string{action},
+		ResourceArns:    []string{secret.ARN},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to simulate %s of secret %s by role %s: %w", action, secret.Name, role, err)
+	}
+	if len(output.EvaluationResults) == 0 {
+		return fmt.Errorf("no result of simulating %s of secret %s by role %s", action, secret.Name, role)
+	}
+	for _, result := range output.EvaluationResults {
+		if result.EvalDecision != iamtypes.PolicyEvaluationDecisionTypeAllowed {
+			return fmt.Errorf("role %s isn't allowed to %s on secret %s: %s", role, action, secret.Name, result.EvalDecision)
+		}
+	}
+	return nil
+}
+
+// ValidateSecrets will check, that secrets of Glue Job exist and the role of Glue Job can read them
+func (g *Job) ValidateSecrets() error {
+	if len(g.job.AWSSecrets) == 0 {
+		return nil
+	}
+	return ValidateSecrets(g.ctx, g.secretsClients, g.job.Role, g.accountID, g.job.AWSSecrets)
+}
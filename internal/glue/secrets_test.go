@@ -0,0 +1,194 @@
+package glue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+const (
+	testSecretARN    = "arn:aws:secretsmanager:eu-west-1:123456789012:secret:etl/db-AbCdEf"
+	testParameterARN = "arn:aws:ssm:eu-west-1:123456789012:parameter/etl/api-key"
+	testRoleARN      = "arn:aws:iam::123456789012:role/glue-etl"
+)
+
+// fakeSecrets is a stand-in of Secrets Manager, SSM and IAM endpoints
+type fakeSecrets struct {
+	// secrets are ARNs of existing Secrets Manager secrets
+	secrets []string
+	// parameters are names of existing SSM parameters
+	parameters []string
+	// allowed are "role resource" pairs, which are allowed to be read
+	allowed []string
+	// regions are the signing regions of requests, every secret is described and then simulated
+	regions []string
+}
+
+// newFakeSecretsClients will return clients of the stand-in endpoints
+func newFakeSecretsClients(t *testing.T, fake *fakeSecrets) SecretsClients {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+	creds := credentials.NewStaticCredentialsProvider("key", "secret", "")
+	return SecretsClients{
+		SecretsManager: secretsmanager.New(secretsmanager.Options{
+			Region: "us-east-1", BaseEndpoint: aws.String(server.URL), Credentials: creds, RetryMaxAttempts: 1,
+		}),
+		SSM: ssm.New(ssm.Options{
+			Region: "us-east-1", BaseEndpoint: aws.String(server.URL), Credentials: creds, RetryMaxAttempts: 1,
+		}),
+		IAM: iam.New(iam.Options{
+			Region: "us-east-1", BaseEndpoint: aws.String(server.URL), Credentials: creds, RetryMaxAttempts: 1,
+		}),
+	}
+}
+
+func (f *fakeSecrets) serve(w http.ResponseWriter, r *http.Request) {
+	// Authorization: AWS4-HMAC-SHA256 Credential=key/date/region/service/aws4_request, ...
+	if scope := strings.Split(r.Header.Get("Authorization"), "/"); len(scope) > 2 {
+		f.regions = append(f.regions, scope[2])
+	}
+	switch r.Header.Get("X-Amz-Target") {
+	case "secretsmanager.DescribeSecret":
+		var input struct{ SecretId string }
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		if !slices.Contains(f.secrets, input.SecretId) {
+			w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"__type":"ResourceNotFoundException","Message":"Secrets Manager can't find the secret"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"ARN":%q}`, input.SecretId)
+	case "AmazonSSM.DescribeParameters":
+		var input struct {
+			ParameterFilters []struct{ Values []string }
+		}
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		name := input.ParameterFilters[0].Values[0]
+		if !slices.Contains(f.parameters, name) {
+			fmt.Fprint(w, `{"Parameters":[]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"Parameters":[{"Name":%q}]}`, name)
+	default:
+		if err := r.ParseForm(); err != nil || r.Form.Get("Action") != "SimulatePrincipalPolicy" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		decision := "implicitDeny"
+		resource := r.Form.Get("ResourceArns.member.1")
+		if slices.Contains(f.allowed, r.Form.Get("PolicySourceArn")+" "+resource) {
+			decision = "allowed"
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, `<SimulatePrincipalPolicyResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <SimulatePrincipalPolicyResult>
+    <IsTruncated>false</IsTruncated>
+    <EvaluationResults>
+      <member>
+        <EvalActionName>%s</EvalActionName>
+        <EvalResourceName>%s</EvalResourceName>
+        <EvalDecision>%s</EvalDecision>
+      </member>
+    </EvaluationResults>
+  </SimulatePrincipalPolicyResult>
+</SimulatePrincipalPolicyResponse>`, r.Form.Get("ActionNames.member.1"), resource, decision)
+	}
+}
+
+func TestValidateSecrets(t *testing.T) {
+	fake := &fakeSecrets{
+		secrets:    []string{testSecretARN},
+		parameters: []string{"/etl/api-key"},
+		allowed:    []string{testRoleARN + " " + testSecretARN, testRoleARN + " " + testParameterARN},
+	}
+	clients := newFakeSecretsClients(t, fake)
+	secrets := []awsv1alpha1.GlueJobAWSSecret{
+		{Name: "db", ARN: testSecretARN},
+		{Name: "api-key", ARN: testParameterARN},
+	}
+
+	// role is set by name in the account of Glue Job
+	err := ValidateSecrets(context.Background(), clients, "glue-etl", "123456789012", secrets)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.regions) != 4 || fake.regions[0] != "eu-west-1" || fake.regions[2] != "eu-west-1" {
+		t.Errorf("expected secrets to be described in their region, got %v", fake.regions)
+	}
+
+	err = ValidateSecrets(context.Background(), clients, "arn:aws:iam::123456789012:role/other", "", secrets)
+	if err == nil || !strings.Contains(err.Error(), "isn't allowed to secretsmanager:GetSecretValue") ||
+		!strings.Contains(err.Error(), "isn't allowed to ssm:GetParameter") {
+		t.Errorf("expected errors of both secrets not readable by the role, got %v", err)
+	}
+}
+
+func TestValidateSecretsMissing(t *testing.T) {
+	clients := newFakeSecretsClients(t, &fakeSecrets{allowed: []string{testRoleARN + " " + testSecretARN}})
+
+	err := ValidateSecrets(context.Background(), clients, testRoleARN, "", []awsv1alpha1.GlueJobAWSSecret{
+		{Name: "db", ARN: testSecretARN},
+	})
+	if err == nil || !strings.Contains(err.Error(), "secret db doesn't exist") {
+		t.Errorf("expected error of missing secret, got %v", err)
+	}
+	err = ValidateSecrets(context.Background(), clients, testRoleARN, "", []awsv1alpha1.GlueJobAWSSecret{
+		{Name: "api-key", ARN: testParameterARN},
+	})
+	if err == nil || !strings.Contains(err.Error(), "parameter api-key doesn't exist") {
+		t.Errorf("expected error of missing parameter, got %v", err)
+	}
+}
+
+func TestSecretArguments(t *testing.T) {
+	secrets := []awsv1alpha1.GlueJobAWSSecret{
+		{Name: "db", ARN: testSecretARN},
+		{Name: "api-key", ARN: testParameterARN},
+	}
+	expected := map[string]string{
+		"--db-secret-arn":         testSecretARN,
+		"--api-key-parameter-arn": testParameterARN,
+	}
+	if args := secretArguments(secrets); !maps.Equal(args, expected) {
+		t.Errorf("expected arguments %v, got %v", expected, args)
+	}
+
+	spec := &awsv1alpha1.GlueJobSpec{
+		AWSSecrets:       secrets,
+		DefaultArguments: map[string]string{"--db-secret-arn": "arn:aws:secretsmanager:eu-west-1:1:secret:x"},
+	}
+	if err := validateSecrets(spec); err == nil {
+		t.Error("expected error of secret argument set in default arguments")
+	}
+	spec = &awsv1alpha1.GlueJobSpec{
+		AWSSecrets: []awsv1alpha1.GlueJobAWSSecret{{Name: "db", ARN: "arn:aws:s3:::bucket/key"}},
+	}
+	if err := validateSecrets(spec); err == nil {
+		t.Error("expected error of ARN, which isn't secret or parameter")
+	}
+}
+
+func TestParameterName(t *testing.T) {
+	for resource, expected := range map[string]string{
+		"parameter/api-key":     "api-key",
+		"parameter/etl/api-key": "/etl/api-key",
+	} {
+		if name := parameterName(resource); name != expected {
+			t.Errorf("expected name %s of %s, got %s", expected, resource, name)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package glue
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsglue "github.com/aws/aws-sdk-go-v2/service/glue"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+)
+
+// sourceControlDetails will return source control configuration of Glue Job for the spec,
+// the auth token is only passed to pull and push
+func (g *Job) sourceControlDetails() *types.SourceControlDetails {
+	sc := g.job.SourceControlDetails
+	if sc == nil {
+		return nil
+	}
+	details := &types.SourceControlDetails{
+		Provider:     types.SourceControlProvider(sc.Provider),
+		Repository:   aws.String(sc.Repository),
+		AuthStrategy: types.SourceControlAuthStrategy(sc.AuthStrategy),
+	}
+	if sc.Owner != "" {
+		details.Owner = aws.String(sc.Owner)
+	}
+	if sc.Branch != "" {
+		details.Branch = aws.String(sc.Branch)
+	}
+	if sc.Folder != "" {
+		details.Folder = aws.String(sc.Folder)
+	}
+	return details
+}
+
+// sourceControlChanged will return true, if source control configuration differs from the live one
+func sourceControlChanged(update, live *types.SourceControlDetails) bool {
+	if update == nil {
+		return false
+	}
+	if live == nil {
+		return true
+	}
+	return update.Provider != live.Provider ||
+		aws.ToString(update.Repository) != aws.ToString(live.Repository) ||
+		aws.ToString(update.Owner) != aws.ToString(live.Owner) ||
+		aws.ToString(update.Branch) != aws.ToString(live.Branch) ||
+		aws.ToString(update.Folder) != aws.ToString(live.Folder) ||
+		(update.AuthStrategy != "" && update.AuthStrategy != live.AuthStrategy)
+}
+
+// PullFromSourceControl will update Glue Job from its source control repository and return the synced commit
+func (g *Job) PullFromSourceControl(token string) (string, error) {
+	details, err := g.sourceControlAction()
+	if err != nil {
+		return "", err
+	}
+	_, err = g.awsClient.UpdateJobFromSourceControl(g.ctx, &awsglue.UpdateJobFromSourceControlInput{
+		JobName:         aws.String(g.job.Name),
+		Provider:        details.Provider,
+		RepositoryName:  details.Repository,
+		RepositoryOwner: details.Owner,
+		BranchName:      details.Branch,
+		Folder:          details.Folder,
+		AuthStrategy:    details.AuthStrategy,
+		AuthToken:       authToken(token),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to pull Glue Job %s from source control: %w", g.job.Name, err)
+	}
+	return g.sourceControlCommit()
+}
+
+// PushToSourceControl will update source control repository from Glue Job and return the synced commit
+func (g *Job) PushToSourceControl(token string) (string, error) {
+	details, err := g.sourceControlAction()
+	if err != nil {
+		return "", err
+	}
+	_, err = g.awsClient.UpdateSourceControlFromJob(g.ctx, &awsglue.UpdateSourceControlFromJobInput{
+		JobName:         aws.String(g.job.Name),
+		Provider:        details.Provider,
+		RepositoryName:  details.Repository,
+		RepositoryOwner: details.Owner,
+		BranchName:      details.Branch,
+		Folder:          details.Folder,
+		AuthStrategy:    details.AuthStrategy,
+		AuthToken:       authToken(token),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to push Glue Job %s to source control: %w", g.job.Name, err)
+	}
+	return g.sourceControlCommit()
+}
+
+// sourceControlAction will return source control configuration, if Glue Job can be pulled or pushed
+func (g *Job) sourceControlAction() (*types.SourceControlDetails, error) {
+	if g.conflict != nil {
+		return nil, g.conflict
+	}
+	details := g.sourceControlDetails()
+	if details == nil {
+		return nil, fmt.Errorf("source control isn't configured for Glue Job %s", g.job.Name)
+	}
+	return details, nil
+}
+
+// sourceControlCommit will return the last commit synced with Glue Job
+func (g *Job) sourceControlCommit() (string, error) {
+	live, err := g.getJob()
+	if err != nil {
+		return "", err
+	}
+	if live.SourceControlDetails == nil {
+		return "", nil
+	}
+	return aws.ToString(live.SourceControlDetails.LastCommitId), nil
+}
+
+// authToken will return auth token of source control action, empty token isn't sent
+func authToken(token string) *string {
+	if token == "" {
+		return nil
+	}
+	return aws.String(token)
+}
@@ -0,0 +1,162 @@
+package glue
+
+import (
+	"fmt"
+	"strings"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+)
+
+const (
+	// Glue Job command names
+	glueETL       = "glueetl"
+	glueStreaming = "gluestreaming"
+	glueRay       = "glueray"
+
+	// Defaults for glueray jobs
+	rayDefaultRuntime       = "Ray2.4"
+	rayPythonVersion        = "3.9"
+	rayGlueVersion          = "4.0"
+	rayDefaultWorkerType    = string(types.WorkerTypeZ2x)
+	rayDefaultExecutionCls  = string(types.ExecutionClassStandard)
+	defaultWorkerType       = string(types.WorkerTypeG1x)
+	defaultExecutionClass   = string(types.ExecutionClassFlex)
+	defaultMaxConcurrentRun = 1
+
+	// Ray job arguments, see
+	// https://docs.aws.amazon.com/glue/latest/dg/author-job-ray-job-parameters.html
+	rayArgPipInstall  = "--pip-install"
+	rayArgS3PyModules = "--s3-py-modules"
+	rayArgWorkingDir  = "--working-dir"
+	rayArgMinWorkers  = "--min-workers"
+)
+
+// isRayJob will return true if GlueJob spec describes a Ray job
+func isRayJob(spec *awsv1alpha1.GlueJobSpec) bool {
+	return strings.ToLower(spec.Command.Name) == glueRay
+}
+
+// withDefaults will return copy of GlueJob spec with defaults,
+// which depend on the kind of the job, applied
+func withDefaults(spec awsv1alpha1.GlueJobSpec) awsv1alpha1.GlueJobSpec {
+	job := *spec.DeepCopy()
+	if job.ExecutionProperty == nil {
+		job.ExecutionProperty = &awsv1alpha1.GlueJobExecutionProperty{
+			MaxConcurrentRuns: defaultMaxConcurrentRun,
+		}
+	}
+	if !isRayJob(&job) {
+		if job.WorkerType == "" {
+			job.WorkerType = defaultWorkerType
+		}
+		if job.ExecutionClass == "" {
+			job.ExecutionClass = defaultExecutionClass
+		}
+		return job
+	}
+	if job.Command.Runtime == "" {
+		job.Command.Runtime = rayDefaultRuntime
+	}
+	if job.WorkerType == "" {
+		job.WorkerType = rayDefaultWorkerType
+	}
+	if job.ExecutionClass == "" {
+		job.ExecutionClass = rayDefaultExecutionCls
+	}
+	return job
+}
+
+// ApplyPlacement will override GlueJob spec with script location, role and arguments of placement
+func ApplyPlacement(spec *awsv1alpha1.GlueJobSpec, placement *awsv1alpha1.GlueJobPlacement) {
+	if placement.ScriptLocation != "" {
+		spec.Command.ScriptLocation = placement.ScriptLocation
+		spec.Command.ScriptVersionID = ""
+		spec.Command.ScriptSource = nil
+	}
+	if placement.Role != "" {
+		spec.Role = placement.Role
+	}
+	if len(placement.DefaultArguments) == 0 {
+		return
+	}
+	args := make(map[string]string, len(spec.DefaultArguments)+len(placement.DefaultArguments))
+	for key, value := range spec.DefaultArguments {
+		args[key] = value
+	}
+	for key, value := range placement.DefaultArguments {
+		args[key] = value
+	}
+	spec.DefaultArguments = args
+}
+
+// validateSpec will check, that GlueJob spec (with defaults applied) is valid for its kind
+func validateSpec(spec *awsv1alpha1.GlueJobSpec) error {
+	err := validateSecrets(spec)
+	if err != nil {
+		return err
+	}
+	if spec.MaintenanceWindow != "" && strings.ToLower(spec.Command.Name) != glueStreaming {
+		return fmt.Errorf("maintenance window can only be used with %s command, got %s", glueStreaming, spec.Command.Name)
+	}
+	if !isRayJob(spec) {
+		if spec.Ray != nil {
+			return fmt.Errorf("ray settings can only be used with %s command, got %s", glueRay, spec.Command.Name)
+		}
+		if spec.WorkerType == rayDefaultWorkerType {
+			return fmt.Errorf("worker type %s can only be used with %s command", rayDefaultWorkerType, glueRay)
+		}
+		return nil
+	}
+	if spec.WorkerType != rayDefaultWorkerType {
+		return fmt.Errorf("%s jobs support only %s worker type, got %s", glueRay, rayDefaultWorkerType, spec.WorkerType)
+	}
+	if spec.ExecutionClass != rayDefaultExecutionCls {
+		return fmt.Errorf("%s jobs support only %s execution class, got %s", glueRay, rayDefaultExecutionCls, spec.ExecutionClass)
+	}
+	if spec.GlueVersion != rayGlueVersion {
+		return fmt.Errorf("%s jobs require Glue version %s, got %s", glueRay, rayGlueVersion, spec.GlueVersion)
+	}
+	if !strings.HasPrefix(spec.Command.Runtime, "Ray") {
+		return fmt.Errorf("%s jobs require Ray runtime (e.g. %s), got %s", glueRay, rayDefaultRuntime, spec.Command.Runtime)
+	}
+	if spec.Ray == nil {
+		return nil
+	}
+	if spec.Ray.MinWorkers != nil && *spec.Ray.MinWorkers > spec.NumberOfWorkers {
+		return fmt.Errorf("ray minWorkers %d can't be greater than numberOfWorkers %d", *spec.Ray.MinWorkers, spec.NumberOfWorkers)
+	}
+	for _, module := range spec.Ray.S3PyModules {
+		if !strings.HasPrefix(module, "s3://") {
+			return fmt.Errorf("ray s3PyModules must be S3 paths, got %s", module)
+		}
+	}
+	// Ray arguments must be set only via ray settings to avoid ambiguity
+	for _, arg := range []string{rayArgPipInstall, rayArgS3PyModules, rayArgWorkingDir, rayArgMinWorkers} {
+		if _, ok := spec.DefaultArguments[arg]; ok {
+			return fmt.Errorf("argument %s must be set via ray settings, not via defaultArguments", arg)
+		}
+	}
+	return nil
+}
+
+// rayArguments will return Glue Job arguments for Ray settings
+func rayArguments(ray *awsv1alpha1.GlueJobRay) map[string]string {
+	args := make(map[string]string)
+	if ray == nil {
+		return args
+	}
+	if len(ray.PipInstall) > 0 {
+		args[rayArgPipInstall] = strings.Join(ray.PipInstall, ",")
+	}
+	if len(ray.S3PyModules) > 0 {
+		args[rayArgS3PyModules] = strings.Join(ray.S3PyModules, ",")
+	}
+	if ray.WorkingDir != "" {
+		args[rayArgWorkingDir] = ray.WorkingDir
+	}
+	if ray.MinWorkers != nil {
+		args[rayArgMinWorkers] = fmt.Sprintf("%d", *ray.MinWorkers)
+	}
+	return args
+}
@@ -0,0 +1,150 @@
+package glue
+
+import (
+	"testing"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+)
+
+func TestWithDefaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		command        string
+		workerType     string
+		executionClass string
+		runtime        string
+	}{
+		{name: "etl", command: "glueetl", workerType: "G.1X", executionClass: "FLEX"},
+		{name: "ray", command: "glueray", workerType: "Z.2X", executionClass: "STANDARD", runtime: "Ray2.4"},
+		{name: "ray in other case", command: "GlueRay", workerType: "Z.2X", executionClass: "STANDARD", runtime: "Ray2.4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := withDefaults(awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: tt.command}})
+			if job.WorkerType != tt.workerType || job.ExecutionClass != tt.executionClass ||
+				job.Command.Runtime != tt.runtime {
+				t.Errorf("expected %s/%s/%s, got %s/%s/%s", tt.workerType, tt.executionClass, tt.runtime,
+					job.WorkerType, job.ExecutionClass, job.Command.Runtime)
+			}
+			if job.ExecutionProperty == nil || job.ExecutionProperty.MaxConcurrentRuns != 1 {
+				t.Errorf("expected default execution property, got %v", job.ExecutionProperty)
+			}
+		})
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	minWorkers := int32(3)
+	tests := []struct {
+		name    string
+		spec    awsv1alpha1.GlueJobSpec
+		wantErr bool
+	}{
+		{name: "etl", spec: awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: "glueetl"}}},
+		{
+			name:    "etl with ray settings",
+			spec:    awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: "glueetl"}, Ray: &awsv1alpha1.GlueJobRay{}},
+			wantErr: true,
+		},
+		{
+			name:    "etl with ray worker type",
+			spec:    awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: "glueetl"}, WorkerType: "Z.2X"},
+			wantErr: true,
+		},
+		{name: "ray", spec: awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: "glueray"}, GlueVersion: "4.0"}},
+		{
+			name: "ray with settings",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command:         awsv1alpha1.GlueJobCommand{Name: "glueray", Runtime: "Ray2.4"},
+				GlueVersion:     "4.0",
+				NumberOfWorkers: 5,
+				Ray: &awsv1alpha1.GlueJobRay{
+					PipInstall:  []string{"pandas==2.0.3"},
+					S3PyModules: []string{"s3://bucket/modules.zip"},
+					MinWorkers:  &minWorkers,
+				},
+			},
+		},
+		{
+			name:    "ray with G.1X worker type",
+			spec:    awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: "glueray"}, GlueVersion: "4.0", WorkerType: "G.1X"},
+			wantErr: true,
+		},
+		{
+			name: "ray with FLEX execution class",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command: awsv1alpha1.GlueJobCommand{Name: "glueray"}, GlueVersion: "4.0", ExecutionClass: "FLEX",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "ray with Glue version 3.0",
+			spec:    awsv1alpha1.GlueJobSpec{Command: awsv1alpha1.GlueJobCommand{Name: "glueray"}, GlueVersion: "3.0"},
+			wantErr: true,
+		},
+		{
+			name: "ray with Python runtime",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command: awsv1alpha1.GlueJobCommand{Name: "glueray", Runtime: "Python3.9"}, GlueVersion: "4.0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "ray with more min workers than workers",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command:         awsv1alpha1.GlueJobCommand{Name: "glueray"},
+				GlueVersion:     "4.0",
+				NumberOfWorkers: 2,
+				Ray:             &awsv1alpha1.GlueJobRay{MinWorkers: &minWorkers},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ray with local modules",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command:     awsv1alpha1.GlueJobCommand{Name: "glueray"},
+				GlueVersion: "4.0",
+				Ray:         &awsv1alpha1.GlueJobRay{S3PyModules: []string{"modules.zip"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ray argument without ray settings",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command:          awsv1alpha1.GlueJobCommand{Name: "glueray"},
+				GlueVersion:      "4.0",
+				DefaultArguments: map[string]string{"--pip-install": "pandas"},
+			},
+		},
+		{
+			name: "ray argument with ray settings",
+			spec: awsv1alpha1.GlueJobSpec{
+				Command:          awsv1alpha1.GlueJobCommand{Name: "glueray"},
+				GlueVersion:      "4.0",
+				DefaultArguments: map[string]string{"--pip-install": "pandas"},
+				Ray:              &awsv1alpha1.GlueJobRay{WorkingDir: "s3://bucket/ray"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := withDefaults(tt.spec)
+			if err := validateSpec(&spec); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRayJobCommand(t *testing.T) {
+	job := &Job{job: withDefaults(awsv1alpha1.GlueJobSpec{
+		Command: awsv1alpha1.GlueJobCommand{Name: "glueray", PythonVersion: 3},
+	})}
+	command := job.jobCommand()
+	// Ray jobs support only the Python version of the Ray runtime
+	if *command.PythonVersion != rayPythonVersion || *command.Runtime != rayDefaultRuntime {
+		t.Errorf("expected Python %s with %s, got Python %s with %s", rayPythonVersion, rayDefaultRuntime,
+			*command.PythonVersion, *command.Runtime)
+	}
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "glue_jobs_operator"
+
+var (
+	// OrphanedJobs is the number of owned Glue Jobs on AWS without matching GlueJob
+	OrphanedJobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "orphaned_jobs",
+		Help:      "Number of Glue Jobs owned by the operator without matching GlueJob",
+	})
+	// OrphanedJobsDeleted is the number of orphaned Glue Jobs deleted by garbage collector
+	OrphanedJobsDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orphaned_jobs_deleted_total",
+		Help:      "Number of orphaned Glue Jobs deleted by the garbage collector",
+	})
+	// OrphanGCErrors is the number of failed garbage collector runs
+	OrphanGCErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orphan_gc_errors_total",
+		Help:      "Number of failed orphaned Glue Jobs garbage collector runs",
+	})
+	// ShardOwnedGlueJobs is the number of GlueJobs reconciled by the shard of this instance
+	ShardOwnedGlueJobs = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "shard_owned_gluejobs",
+		Help:      "Number of GlueJobs reconciled by the shard of this operator instance",
+	}, []string{"shard"})
+)
+
+func init() {
+	// Register custom metrics with the global controller-runtime registry
+	metrics.Registry.MustRegister(
+		OrphanedJobs,
+		OrphanedJobsDeleted,
+		OrphanGCErrors,
+		ShardOwnedGlueJobs,
+	)
+}
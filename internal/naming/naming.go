@@ -0,0 +1,108 @@
+package naming
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+const (
+	// DefaultTemplate is the template of derived Glue Job names. Underscore isn't valid in
+	// cluster IDs, namespaces and names, so derived names of different GlueJobs never collide
+	DefaultTemplate = "{{cluster}}_{{namespace}}_{{name}}"
+	// MaxJobNameLength is the max length of Glue Job name
+	MaxJobNameLength = 255
+	// hashLength is the length of hash suffix of truncated Glue Job names
+	hashLength = 8
+)
+
+// JobName will return the name of Glue Job on AWS for GlueJob. It's spec.name, if it's set,
+// otherwise it's derived from the configured template. Names must start with the prefix
+// configured for the namespace of GlueJob.
+func JobName(cfg config.OperatorConfig, gj *awsv1alpha1.GlueJob) (string, error) {
+	prefix := cfg.NamespacePrefixes[gj.Namespace]
+	if gj.Spec.Name != "" {
+		if !strings.HasPrefix(gj.Spec.Name, prefix) {
+			return "", fmt.Errorf("name %s must start with prefix %s configured for namespace %s",
+				gj.Spec.Name, prefix, gj.Namespace)
+		}
+		return gj.Spec.Name, nil
+	}
+	template := cfg.JobNameTemplate
+	if template == "" {
+		template = DefaultTemplate
+	}
+	name := strings.NewReplacer(
+		"{{cluster}}", cfg.ClusterID,
+		"{{namespace}}", gj.Namespace,
+		"{{name}}", gj.Name,
+	).Replace(template)
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+	return truncate(name), nil
+}
+
+// truncate will truncate name to the max length of Glue Job name,
+// truncated names get hash of the full name as suffix to stay unique
+func truncate(name string) string {
+	if len(name) <= MaxJobNameLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:hashLength]
+	return name[:MaxJobNameLength-len(suffix)] + suffix
+}
+
+// CheckRenames will return error, if Glue Job names derived for GlueJobs without spec.name differ from
+// their status.resolvedName, e.g. after jobNameTemplate, clusterID or namespacePrefixes were changed.
+// The operator must not start then, as it would create new Glue Jobs and leave the old ones behind.
+// GlueJobs of other instances of the operator (not watched namespaces and other shards) are skipped.
+func CheckRenames(ctx context.Context, reader client.Reader, cfg config.OperatorConfig) error {
+	glueJobs := make([]awsv1alpha1.GlueJob, 0)
+	namespaces := cfg.WatchNamespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	// namespaces are listed one by one, as the operator may have access to watched namespaces only
+	for _, namespace := range namespaces {
+		list := &awsv1alpha1.GlueJobList{}
+		err := reader.List(ctx, list, client.InNamespace(namespace))
+		if err != nil {
+			return fmt.Errorf("failed to list GlueJobs: %w", err)
+		}
+		glueJobs = append(glueJobs, list.Items...)
+	}
+	renamed := make([]string, 0)
+	for i := range glueJobs {
+		gj := &glueJobs[i]
+		if gj.Spec.Name != "" || gj.Status.ResolvedName == "" || !cfg.InShard(gj.Namespace, gj.Name, gj.Labels) {
+			continue
+		}
+		name, err := JobName(cfg, gj)
+		if err != nil || name == gj.Status.ResolvedName {
+			// invalid names are reported by the GlueJob reconciler
+			continue
+		}
+		watched, err := cfg.WatchesNamespace(ctx, reader, gj.Namespace)
+		if err != nil {
+			return err
+		}
+		if watched {
+			renamed = append(renamed, fmt.Sprintf("%s/%s (%s to %s)", gj.Namespace, gj.Name, gj.Status.ResolvedName, name))
+		}
+	}
+	if len(renamed) > 0 {
+		return fmt.Errorf("names of Glue Jobs would change: %s. Restore jobNameTemplate, clusterID and "+
+			"namespacePrefixes, or set spec.name of the GlueJobs to their status.resolvedName",
+			strings.Join(renamed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package naming
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+)
+
+// newGlueJob will return GlueJob with spec.name and status.resolvedName
+func newGlueJob(namespace, name, specName, resolvedName string) *awsv1alpha1.GlueJob {
+	return &awsv1alpha1.GlueJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       awsv1alpha1.GlueJobSpec{Name: specName},
+		Status:     awsv1alpha1.GlueJobStatus{ResolvedName: resolvedName},
+	}
+}
+
+func TestJobName(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.OperatorConfig
+		glueJob  *awsv1alpha1.GlueJob
+		expected string
+		invalid  bool
+	}{
+		{
+			name:     "spec.name",
+			config:   config.OperatorConfig{ClusterID: "prod"},
+			glueJob:  newGlueJob("team-a", "etl", "custom", ""),
+			expected: "custom",
+		},
+		{
+			name:     "default template",
+			config:   config.OperatorConfig{ClusterID: "prod"},
+			glueJob:  newGlueJob("team-a", "etl", "", ""),
+			expected: "prod_team-a_etl",
+		},
+		{
+			name:     "names of different GlueJobs don't collide",
+			config:   config.OperatorConfig{ClusterID: "prod"},
+			glueJob:  newGlueJob("team", "a-etl", "", ""),
+			expected: "prod_team_a-etl",
+		},
+		{
+			name:     "custom template",
+			config:   config.OperatorConfig{ClusterID: "prod", JobNameTemplate: "{{namespace}}.{{name}}"},
+			glueJob:  newGlueJob("team-a", "etl", "", ""),
+			expected: "team-a.etl",
+		},
+		{
+			name: "prefix is prepended",
+			config: config.OperatorConfig{
+				ClusterID: "prod", NamespacePrefixes: map[string]string{"team-a": "ta-"},
+			},
+			glueJob:  newGlueJob("team-a", "etl", "", ""),
+			expected: "ta-prod_team-a_etl",
+		},
+		{
+			name: "spec.name without prefix",
+			config: config.OperatorConfig{
+				ClusterID: "prod", NamespacePrefixes: map[string]string{"team-a": "ta-"},
+			},
+			glueJob: newGlueJob("team-a", "etl", "custom", ""),
+			invalid: true,
+		},
+		{
+			name:     "long name is truncated",
+			config:   config.OperatorConfig{ClusterID: "prod"},
+			glueJob:  newGlueJob("team-a", strings.Repeat("a", 300), "", ""),
+			expected: "prod_team-a_" + strings.Repeat("a", MaxJobNameLength-len("prod_team-a_")-hashLength-1) + "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := JobName(tt.config, tt.glueJob)
+			if (err != nil) != tt.invalid {
+				t.Fatalf("expected invalid %v, got %v", tt.invalid, err)
+			}
+			if len(name) > MaxJobNameLength {
+				t.Errorf("expected name of max %d characters, got %d", MaxJobNameLength, len(name))
+			}
+			if !strings.HasPrefix(name, tt.expected) {
+				t.Errorf("expected name %s, got %s", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestTruncateIsUnique(t *testing.T) {
+	a, b := truncate(strings.Repeat("a", 300)+"1"), truncate(strings.Repeat("a", 300)+"2")
+	if len(a) != MaxJobNameLength || a == b {
+		t.Errorf("expected unique names of max length, got %s and %s", a, b)
+	}
+}
+
+func TestCheckRenames(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.OperatorConfig
+		glueJobs []client.Object
+		renamed  bool
+	}{
+		{
+			name: "names are unchanged",
+			glueJobs: []client.Object{
+				newGlueJob("team-a", "etl", "", "prod_team-a_etl"),
+				newGlueJob("team-a", "custom", "custom", "custom"),
+				newGlueJob("team-a", "new", "", ""),
+			},
+		},
+		{
+			name:     "template changed",
+			glueJobs: []client.Object{newGlueJob("team-a", "etl", "", "prod-team-a-etl")},
+			renamed:  true,
+		},
+		{
+			name:     "GlueJob in not watched namespace",
+			config:   config.OperatorConfig{WatchNamespaces: []string{"team-b"}},
+			glueJobs: []client.Object{newGlueJob("team-a", "etl", "", "prod-team-a-etl")},
+		},
+		{
+			name:     "GlueJob in another shard",
+			config:   config.OperatorConfig{ShardName: "s", ShardSelector: "shard=s"},
+			glueJobs: []client.Object{newGlueJob("team-a", "etl", "", "prod-team-a-etl")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := awsv1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.glueJobs...).Build()
+			tt.config.ClusterID = "prod"
+			err := CheckRenames(context.Background(), reader, tt.config)
+			if (err != nil) != tt.renamed {
+				t.Errorf("expected renamed %v, got %v", tt.renamed, err)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -29,12 +30,18 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	awsv1alpha1 "github.com/90poe/glue-jobs-operator/api/v1alpha1"
 	"github.com/90poe/glue-jobs-operator/controllers"
+	"github.com/90poe/glue-jobs-operator/internal/awsclient"
+	"github.com/90poe/glue-jobs-operator/internal/config"
+	"github.com/90poe/glue-jobs-operator/internal/gc"
+	"github.com/90poe/glue-jobs-operator/internal/gitsource"
+	"github.com/90poe/glue-jobs-operator/internal/naming"
 	"github.com/90poe/glue-jobs-operator/internal/version"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	//+kubebuilder:scaffold:imports
@@ -56,11 +63,14 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var configFile string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&configFile, "config", os.Getenv(config.FileEnv),
+		"The path of the operator config file, settings from Env override it.")
 	opts := zap.Options{
 		Development: false,
 	}
@@ -69,6 +79,28 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	operatorConfig, err := config.Load(configFile)
+	if err != nil {
+		setupLog.Error(err, "unable to get operator config")
+		os.Exit(1)
+	}
+	configStore := config.NewStore(operatorConfig, configFile, ctrl.Log.WithName("config"))
+
+	cacheOptions := cache.Options{}
+	if len(operatorConfig.WatchNamespaces) > 0 {
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config, len(operatorConfig.WatchNamespaces))
+		for _, namespace := range operatorConfig.WatchNamespaces {
+			cacheOptions.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+
+	// leases of shards are looked up in the namespace of the operator during shard handover
+	leaseNamespace := os.Getenv("POD_NAMESPACE")
+	if operatorConfig.Sharded() && enableLeaderElection && leaseNamespace == "" {
+		setupLog.Error(nil, "sharding with leader election requires POD_NAMESPACE env")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
@@ -77,9 +109,11 @@ func main() {
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port: 9443,
 		}),
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "a4b9d8a1.90poe.io",
+		Cache:                   cacheOptions,
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        config.ShardLeaderElectionID(operatorConfig.ShardName),
+		LeaderElectionNamespace: leaseNamespace,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -97,13 +131,53 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = mgr.Add(configStore); err != nil {
+		setupLog.Error(err, "unable to watch operator config file")
+		os.Exit(1)
+	}
+	awsProvider := awsclient.NewProvider(mgr.GetClient(), mgr.GetAPIReader(), operatorConfig)
+	scriptSources := gitsource.NewCache()
 	if err = (&controllers.GlueJobReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Config:         configStore,
+		AWS:            awsProvider,
+		Recorder:       mgr.GetEventRecorderFor("glue-jobs-operator"),
+		APIReader:      mgr.GetAPIReader(),
+		ScriptSources:  scriptSources,
+		LeaseNamespace: leaseNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GlueJob")
 		os.Exit(1)
 	}
+	if err = (&controllers.GlueScriptSourceReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Config:        configStore,
+		Recorder:      mgr.GetEventRecorderFor("glue-jobs-operator"),
+		APIReader:     mgr.GetAPIReader(),
+		ScriptSources: scriptSources,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GlueScriptSource")
+		os.Exit(1)
+	}
+	if operatorConfig.WebhooksEnabled() {
+		jobName := func(gj *awsv1alpha1.GlueJob) (string, error) {
+			return naming.JobName(operatorConfig, gj)
+		}
+		if err = (&awsv1alpha1.GlueJob{}).SetupWebhookWithManager(mgr, jobName); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GlueJob")
+			os.Exit(1)
+		}
+	}
+	if err = (&gc.OrphanCollector{
+		Reader: mgr.GetClient(),
+		AWS:    awsProvider,
+		Config: configStore,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create orphaned Glue Jobs garbage collector")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -115,6 +189,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// changed naming config must not silently rename Glue Jobs, the cache isn't started yet
+	if err := naming.CheckRenames(context.Background(), mgr.GetAPIReader(), operatorConfig); err != nil {
+		setupLog.Error(err, "unable to start with changed naming of Glue Jobs")
+		os.Exit(1)
+	}
+
 	setupLog.Info(fmt.Sprintf("starting manager version=%v, built at=%v, git hash=%v",
 		version.Version, version.BuildDate, version.GitHash))
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {